@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// readCoordinates reads a coordinates file, holding the [x y] coordinates of one vertex per line in the order of the
+// vertex indexes, separated by whitespace or commas. Comments and blank lines are ignored the same way as in an
+// edge-list file.
+func readCoordinates(path string) ([]*pb.Point, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	// The lines are split the same way as the lines of an edge-list file
+	er := &edgeListReader{scanner: bufio.NewScanner(file)}
+
+	var coordinates []*pb.Point
+	for {
+		fields, err := er.nextFields()
+		if err == io.EOF {
+			return coordinates, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("line %d: expected coordinates as [x y], got %d values", er.line, len(fields))
+		}
+
+		x, err := strconv.ParseFloat(fields[0], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid value: %s", er.line, fields[0])
+		}
+		y, err := strconv.ParseFloat(fields[1], 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid value: %s", er.line, fields[1])
+		}
+
+		coordinates = append(coordinates, &pb.Point{X: x, Y: y})
+	}
+}
+
+// parseHeuristic parses the name of the heuristic used by A* search, where an empty name leaves the choice to the
+// server
+func parseHeuristic(name string) (pb.Heuristic, error) {
+	switch name {
+	case "":
+		return pb.Heuristic_HEURISTIC_UNSPECIFIED, nil
+	case "euclidean":
+		return pb.Heuristic_HEURISTIC_EUCLIDEAN, nil
+	case "manhattan":
+		return pb.Heuristic_HEURISTIC_MANHATTAN, nil
+	default:
+		return 0, fmt.Errorf("%s is not a valid heuristic", name)
+	}
+}
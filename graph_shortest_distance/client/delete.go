@@ -7,7 +7,7 @@ import (
 )
 
 // doDelete executes the client request
-func doDelete(client pb.GraphServiceClient, id int32) {
+func doDelete(client pb.GraphServiceClient, id int64) {
 	log.Println("Deleting the specified graph now...")
 
 	res, err := client.Delete(context.Background(), &pb.DeleteRequest{
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// importChunkSize is the number of bytes of the file sent in each message of Import
+const importChunkSize = 1 << 20
+
+// doImportFile executes the client request, uploading a DIMACS file to be parsed by the server
+func doImportFile(client pb.GraphServiceClient, path string) {
+	log.Printf("Importing new graph from %s now...\n", path)
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v\n", path, err)
+	}
+	defer file.Close()
+
+	stream, err := client.Import(context.Background())
+	if err != nil {
+		log.Fatalf("Error while calling Import: %v\n", err)
+	}
+
+	err = stream.Send(&pb.ImportRequest{Format: pb.GraphFormat_GRAPH_FORMAT_DIMACS})
+
+	buf := make([]byte, importChunkSize)
+	for err == nil {
+		n, readErr := file.Read(buf)
+		if n > 0 {
+			err = stream.Send(&pb.ImportRequest{Data: buf[:n]})
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			log.Fatalf("Failed to read %s: %v\n", path, readErr)
+		}
+	}
+
+	// Sending fails with io.EOF once the server ended the stream, whose actual error is then returned by CloseAndRecv
+	if err != nil && err != io.EOF {
+		log.Fatalf("Error while sending data to server: %v\n", err)
+	}
+
+	res, err := stream.CloseAndRecv()
+	handlePostResult(res, err)
+}
+
+// dimacsWriter prints a graph read back with GetGraph in the DIMACS shortest path format, mapping the vertices 0 to
+// n - 1 onto the DIMACS vertices 1 to n. Since the DIMACS arcs are directed, every edge of an undirected graph is
+// printed as two opposite arcs, and the edges without a weight are given weight 1.
+type dimacsWriter struct {
+	w        *bufio.Writer
+	directed bool
+}
+
+// header prints the problem line, from the first response of GetGraph
+func (dw *dimacsWriter) header(id int64, res *pb.GetGraphResponse) {
+	dw.directed = res.Directed
+
+	totalArcs := int64(res.TotalEdges)
+	if !dw.directed {
+		totalArcs *= 2
+	}
+
+	fmt.Fprintf(dw.w, "c graph[id=%d] exported from the graph shortest distance server\n", id)
+	fmt.Fprintf(dw.w, "p sp %d %d\n", res.TotalVertices, totalArcs)
+}
+
+// labels ignores the vertex labels of a labeled graph, since the DIMACS vertices are numbered
+func (dw *dimacsWriter) labels(labels []string) {}
+
+// edges prints the arcs of the edges
+func (dw *dimacsWriter) edges(edges []*pb.Edge) {
+	for _, edge := range edges {
+		weight := int32(1)
+		if edge.Weight != nil {
+			weight = *edge.Weight
+		}
+
+		fmt.Fprintf(dw.w, "a %d %d %d\n", edge.Src+1, edge.Dest+1, weight)
+		if !dw.directed {
+			fmt.Fprintf(dw.w, "a %d %d %d\n", edge.Dest+1, edge.Src+1, weight)
+		}
+	}
+}
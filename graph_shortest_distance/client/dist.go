@@ -5,19 +5,24 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"log"
-	"math"
+	"time"
 
 	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
 )
 
-// doDist executes the client request
-func doDist(client pb.GraphServiceClient, id int32, src int32, dest int32) {
+// doDist executes the client request. The request is given up once the timeout elapses, unless the timeout is 0.
+func doDist(client pb.GraphServiceClient, id int64, src node, dest node, timeout time.Duration) {
 	log.Println("Computing shortest distance now...")
 
-	res, err := client.Dist(context.Background(), &pb.DistRequest{
-		Id:   id,
-		Src:  src,
-		Dest: dest,
+	ctx, cancel := requestContext(timeout)
+	defer cancel()
+
+	res, err := client.Dist(ctx, &pb.DistRequest{
+		Id:        id,
+		Src:       src.index,
+		Dest:      dest.index,
+		SrcLabel:  src.label,
+		DestLabel: dest.label,
 	})
 
 	// Error handling
@@ -32,17 +37,28 @@ func doDist(client pb.GraphServiceClient, id int32, src int32, dest int32) {
 				log.Fatalf("Please check if the specified source node or destination node exist in the graph.\n")
 			} else if sts.Code() == codes.NotFound {
 				log.Fatalf("Please check if the graph ID is correct.\n")
+			} else if sts.Code() == codes.DeadlineExceeded {
+				log.Fatalf("The shortest distance could not be computed within the timeout of %v.\n", timeout)
 			}
 		} else {
 			log.Fatalf("A non gRPC error: %v\n", err)
 		}
 	}
 
-	if res.Result == math.MaxInt32 {
-		log.Printf("The source node [%d] and destination node [%d] in graph[id=%d] are not connected.\n",
+	if !res.Reachable {
+		log.Printf("The source node [%v] and destination node [%v] in graph[id=%d] are not connected.\n",
 			src, dest, id)
 	} else {
-		log.Printf("The shortest distance between node [%d] and node [%d] in graph[id=%d] is: %d\n",
+		log.Printf("The shortest distance between node [%v] and node [%v] in graph[id=%d] is: %d\n",
 			src, dest, id, res.Result)
 	}
 }
+
+// requestContext returns the context for sending a request, which is cancelled once the timeout elapses, unless the
+// timeout is 0
+func requestContext(timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout == 0 {
+		return context.WithCancel(context.Background())
+	}
+	return context.WithTimeout(context.Background(), timeout)
+}
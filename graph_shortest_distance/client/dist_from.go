@@ -0,0 +1,73 @@
+package main
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"log"
+	"time"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// doDistFrom executes the client request, and prints the shortest distance of every vertex reachable from the source
+// node as the responses are received. Only the vertices at most maxDistance away are printed if maxDistance is not
+// negative. The request is given up once the timeout elapses, unless the timeout is 0.
+func doDistFrom(client pb.GraphServiceClient, id int64, src node, maxDistance int64, timeout time.Duration) {
+	log.Println("Computing shortest distances now...")
+
+	ctx, cancel := requestContext(timeout)
+	defer cancel()
+
+	req := &pb.DistFromRequest{
+		Id:       id,
+		Src:      src.index,
+		SrcLabel: src.label,
+	}
+	if maxDistance >= 0 {
+		req.MaxDistance = &maxDistance
+	}
+
+	stream, err := client.DistFrom(ctx, req)
+
+	if err != nil {
+		log.Fatalf("Error while calling DistFrom: %v\n", err)
+	}
+
+	total := 0
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+
+		// Error handling
+		if err != nil {
+			sts, ok := status.FromError(err)
+
+			if ok {
+				log.Printf("Error message from server: %v\n", sts.Message())
+				log.Printf("Error code: %d\n", sts.Code())
+
+				if sts.Code() == codes.InvalidArgument {
+					log.Fatalf("Please check if the specified source node exists in the graph.\n")
+				} else if sts.Code() == codes.NotFound {
+					log.Fatalf("Please check if the graph ID is correct.\n")
+				} else if sts.Code() == codes.DeadlineExceeded {
+					log.Fatalf("The shortest distances could not be computed within the timeout of %v.\n", timeout)
+				}
+				log.Fatalf("The shortest distances could not be computed.\n")
+			} else {
+				log.Fatalf("A non gRPC error: %v\n", err)
+			}
+		}
+
+		for _, vd := range res.Distances {
+			log.Printf("The shortest distance between node [%v] and node [%v] in graph[id=%d] is: %d\n",
+				src, node{index: vd.Vertex, label: vd.Label}, id, vd.Distance)
+		}
+		total += len(res.Distances)
+	}
+
+	log.Printf("%d nodes are reachable from node [%v] in graph[id=%d].\n", total, src, id)
+}
@@ -0,0 +1,102 @@
+package main
+
+import (
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// doDistMatrix executes the client request. The matrix is printed to the standard output as a table, with one row per
+// source node and one column per destination node, once all the rows are received. The request is given up once the
+// timeout elapses, unless the timeout is 0.
+func doDistMatrix(client pb.GraphServiceClient, id int64, srcs []node, dests []node, timeout time.Duration) {
+	log.Println("Computing shortest distance matrix now...")
+
+	ctx, cancel := requestContext(timeout)
+	defer cancel()
+
+	req := &pb.DistMatrixRequest{Id: id}
+	for _, src := range srcs {
+		req.Srcs = append(req.Srcs, src.index)
+		if src.label != "" {
+			req.SrcLabels = append(req.SrcLabels, src.label)
+		}
+	}
+	for _, dest := range dests {
+		req.Dests = append(req.Dests, dest.index)
+		if dest.label != "" {
+			req.DestLabels = append(req.DestLabels, dest.label)
+		}
+	}
+
+	stream, err := client.DistMatrix(ctx, req)
+
+	if err != nil {
+		log.Fatalf("Error while calling DistMatrix: %v\n", err)
+	}
+
+	// The rows are received in any order
+	rows := make([]*pb.DistMatrixResponse, len(srcs))
+	for {
+		res, err := stream.Recv()
+
+		if err == io.EOF {
+			break
+		}
+
+		// Error handling
+		if err != nil {
+			sts, ok := status.FromError(err)
+
+			if ok {
+				log.Printf("Error message from server: %v\n", sts.Message())
+				log.Printf("Error code: %d\n", sts.Code())
+
+				if sts.Code() == codes.InvalidArgument {
+					log.Fatalf("Please check if the specified source nodes and destination nodes exist in the graph.\n")
+				} else if sts.Code() == codes.NotFound {
+					log.Fatalf("Please check if the graph ID is correct.\n")
+				} else if sts.Code() == codes.DeadlineExceeded {
+					log.Fatalf("The shortest distance matrix could not be computed within the timeout of %v.\n",
+						timeout)
+				}
+				log.Fatalf("The shortest distance matrix could not be computed.\n")
+			} else {
+				log.Fatalf("A non gRPC error: %v\n", err)
+			}
+		}
+
+		rows[res.Row] = res
+	}
+
+	log.Printf("The shortest distance matrix of graph[id=%d] is (- stands for the nodes which are not connected):\n",
+		id)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', tabwriter.AlignRight)
+	defer w.Flush()
+
+	header := make([]string, len(dests))
+	for i, dest := range dests {
+		header[i] = dest.String()
+	}
+	fmt.Fprintf(w, "\t%s\t\n", strings.Join(header, "\t"))
+
+	for i, row := range rows {
+		cells := make([]string, len(dests))
+		for j := range cells {
+			cells[j] = "-"
+			if row.Reachable[j] {
+				cells[j] = fmt.Sprint(row.Results[j])
+			}
+		}
+		fmt.Fprintf(w, "%v\t%s\t\n", srcs[i], strings.Join(cells, "\t"))
+	}
+}
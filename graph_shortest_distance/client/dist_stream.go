@@ -1,17 +1,17 @@
 package main
 
 import (
-	"context"
 	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"io"
 	"log"
-	"math"
+	"time"
 )
 
-// doDistStream executes the client request
-func doDistStream(client pb.GraphServiceClient, ids []int32, srcs []int32, dests []int32) {
+// doDistStream executes the client request. The whole stream is given up once the timeout elapses, unless the
+// timeout is 0.
+func doDistStream(client pb.GraphServiceClient, ids []int64, srcs []node, dests []node, timeout time.Duration) {
 	log.Println("Processing multiple shortest distance requests now...")
 
 	// Parameter validation
@@ -21,7 +21,11 @@ func doDistStream(client pb.GraphServiceClient, ids []int32, srcs []int32, dests
 	}
 
 	reqLen := len(ids)
-	stream, err := client.DistStream(context.Background())
+
+	ctx, cancel := requestContext(timeout)
+	defer cancel()
+
+	stream, err := client.DistStream(ctx)
 
 	if err != nil {
 		log.Fatalf("Error while opening stream: %v\n", err)
@@ -31,13 +35,16 @@ func doDistStream(client pb.GraphServiceClient, ids []int32, srcs []int32, dests
 
 	go func() {
 		for i := 0; i < reqLen; i++ {
-			req := pb.DistRequest{
-				Id:   ids[i],
-				Src:  srcs[i],
-				Dest: dests[i],
+			req := &pb.DistRequest{
+				Id:        ids[i],
+				Src:       srcs[i].index,
+				Dest:      dests[i].index,
+				SrcLabel:  srcs[i].label,
+				DestLabel: dests[i].label,
+				RequestId: int64(i),
 			}
-			log.Printf("Sending request: %+v\n", req)
-			stream.Send(&req)
+			log.Printf("Sending request: %v\n", req)
+			stream.Send(req)
 		}
 		stream.CloseSend()
 	}()
@@ -61,6 +68,8 @@ func doDistStream(client pb.GraphServiceClient, ids []int32, srcs []int32, dests
 						log.Fatalf("Please check if the specified source node or destination node exist in the graph.\n")
 					} else if sts.Code() == codes.NotFound {
 						log.Fatalf("Please check if the graph ID is correct.\n")
+					} else if sts.Code() == codes.DeadlineExceeded {
+						log.Fatalf("The shortest distances could not be computed within the timeout of %v.\n", timeout)
 					}
 				} else {
 					log.Fatalf("A non gRPC error: %v\n", err)
@@ -69,12 +78,28 @@ func doDistStream(client pb.GraphServiceClient, ids []int32, srcs []int32, dests
 				break
 			}
 
-			if res.Result == math.MaxInt32 {
-				log.Printf("The source node [%d] and destination node [%d] in graph[id=%d] are not connected.\n",
-					res.Src, res.Dest, res.Id)
+			// The request failed, while the stream carries on with the other requests
+			if res.ErrorCode != int32(codes.OK) {
+				log.Printf("Request #%d failed. Error message from server: %v\n", res.RequestId, res.ErrorMessage)
+				log.Printf("Error code: %d\n", res.ErrorCode)
+
+				if res.ErrorCode == int32(codes.InvalidArgument) {
+					log.Printf("Please check if the specified source node or destination node exist in the graph.\n")
+				} else if res.ErrorCode == int32(codes.NotFound) {
+					log.Printf("Please check if the graph ID is correct.\n")
+				}
+
+				continue
+			}
+
+			src := node{index: res.Src, label: res.SrcLabel}
+			dest := node{index: res.Dest, label: res.DestLabel}
+			if !res.Reachable {
+				log.Printf("The source node [%v] and destination node [%v] in graph[id=%d] are not connected.\n",
+					src, dest, res.Id)
 			} else {
-				log.Printf("The shortest distance between node [%d] and node [%d] in graph[id=%d] is: %d\n",
-					res.Src, res.Dest, res.Id, res.Result)
+				log.Printf("The shortest distance between node [%v] and node [%v] in graph[id=%d] is: %d\n",
+					src, dest, res.Id, res.Result)
 			}
 		}
 		close(streamSync)
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"log"
+	"os"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// exportFormats maps the formats of the export method to their GraphFormat and file extension
+var exportFormats = map[string]struct {
+	format pb.GraphFormat
+	ext    string
+}{
+	"graphml": {format: pb.GraphFormat_GRAPH_FORMAT_GRAPHML, ext: ".graphml"},
+	"dot":     {format: pb.GraphFormat_GRAPH_FORMAT_DOT, ext: ".dot"},
+}
+
+// doExport executes the client request, writing the exported graph to the output file. The highlight is optional,
+// and if given, one shortest path between its two nodes is highlighted in the exported graph.
+func doExport(client pb.GraphServiceClient, id int64, format pb.GraphFormat, highlight *pb.PathHighlight,
+	output string) {
+	log.Println("Exporting the specified graph now...")
+
+	stream, err := client.Export(context.Background(), &pb.ExportRequest{
+		Id:        id,
+		Format:    format,
+		Highlight: highlight,
+	})
+
+	if err != nil {
+		log.Fatalf("Error while calling Export: %v\n", err)
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		log.Fatalf("Failed to create %s: %v\n", output, err)
+	}
+
+	for {
+		res, err := stream.Recv()
+
+		if err == io.EOF {
+			break
+		}
+
+		// Error handling
+		if err != nil {
+			file.Close()
+			os.Remove(output)
+
+			sts, ok := status.FromError(err)
+
+			if ok {
+				log.Printf("Error message from server: %v\n", sts.Message())
+				log.Printf("Error code: %d\n", sts.Code())
+
+				if sts.Code() == codes.InvalidArgument {
+					log.Fatalf("Please check if the specified source node or destination node exist in the graph.\n")
+				} else if sts.Code() == codes.NotFound {
+					log.Fatalf("Please check if the graph ID is correct.\n")
+				}
+				log.Fatalf("The graph[id=%d] could not be exported.\n", id)
+			} else {
+				log.Fatalf("A non gRPC error: %v\n", err)
+			}
+		}
+
+		if _, err = file.Write(res.Data); err != nil {
+			file.Close()
+			os.Remove(output)
+			log.Fatalf("Failed to write %s: %v\n", output, err)
+		}
+	}
+
+	if err = file.Close(); err != nil {
+		log.Fatalf("Failed to write %s: %v\n", output, err)
+	}
+
+	log.Printf("The graph[id=%d] is successfully exported to %s.\n", id, output)
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"unicode"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// edgeListReader parses an edge-list file line by line. Every line holds one edge as a [src dest] pair or a
+// [src dest weight] triple, separated by whitespace or commas. Everything from a '#' to the end of a line is a comment,
+// and blank lines are ignored. The first line may instead hold a single value, the total number of vertices.
+// In a labeled file, the nodes of the edges are given by their labels, and any line holding a single value adds a
+// vertex having this label, so that vertices without edges can be given as well.
+type edgeListReader struct {
+	scanner *bufio.Scanner
+	// labeled is set when the nodes are given by their labels
+	labeled bool
+	// line is the number of the last line read
+	line int
+	// totalVertices is given by the header of the file, or -1 if the file has no header
+	totalVertices int64
+	// pending is the edge read while looking for the header, which is returned by the next chunk
+	pending *pb.Edge
+}
+
+// newEdgeListReader starts reading an edge-list file, up to its header or its first edge. A labeled file has no
+// header.
+func newEdgeListReader(r io.Reader, labeled bool) (*edgeListReader, error) {
+	er := &edgeListReader{scanner: bufio.NewScanner(r), labeled: labeled, totalVertices: -1}
+	if labeled {
+		return er, nil
+	}
+
+	fields, err := er.nextFields()
+	if err == io.EOF {
+		return er, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(fields) == 1 {
+		totalVertices, err := er.parseInt64(fields[0])
+		if err != nil {
+			return nil, err
+		}
+		er.totalVertices = totalVertices
+	} else if er.pending, err = er.parseEdge(fields); err != nil {
+		return nil, err
+	}
+
+	return er, nil
+}
+
+// readChunk returns the next edges and vertex labels of the file, up to maxBytes of encoded size, along with their
+// size, or io.EOF once the whole file has been read
+func (er *edgeListReader) readChunk(maxBytes int) (*pb.EdgeChunk, int, error) {
+	chunk := &pb.EdgeChunk{}
+	size := 0
+	if er.pending != nil {
+		chunk.Edges = append(chunk.Edges, er.pending)
+		size += messageFieldSize(er.pending)
+		er.pending = nil
+	}
+
+	for size < maxBytes {
+		fields, err := er.nextFields()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, 0, err
+		}
+
+		if er.labeled && len(fields) == 1 {
+			chunk.Labels = append(chunk.Labels, fields[0])
+			size += stringFieldSize(fields[0])
+			continue
+		}
+
+		edge, err := er.parseEdge(fields)
+		if err != nil {
+			return nil, 0, err
+		}
+		chunk.Edges = append(chunk.Edges, edge)
+		size += messageFieldSize(edge)
+	}
+
+	if len(chunk.Edges) == 0 && len(chunk.Labels) == 0 {
+		return nil, 0, io.EOF
+	}
+	return chunk, size, nil
+}
+
+// nextFields returns the fields of the next line which is neither blank nor a comment, or io.EOF at the end of the file
+func (er *edgeListReader) nextFields() ([]string, error) {
+	for er.scanner.Scan() {
+		er.line++
+
+		text := er.scanner.Text()
+		if i := strings.IndexByte(text, '#'); i >= 0 {
+			text = text[:i]
+		}
+
+		fields := strings.FieldsFunc(text, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+		if len(fields) > 0 {
+			return fields, nil
+		}
+	}
+
+	if err := er.scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, io.EOF
+}
+
+// parseEdge parses the fields of a line holding an edge
+func (er *edgeListReader) parseEdge(fields []string) (*pb.Edge, error) {
+	if len(fields) != 2 && len(fields) != 3 {
+		return nil, fmt.Errorf("line %d: expected an edge as [src dest] or [src dest weight], got %d values",
+			er.line, len(fields))
+	}
+
+	edge := &pb.Edge{}
+	if er.labeled {
+		edge.SrcLabel = fields[0]
+		edge.DestLabel = fields[1]
+	} else {
+		src, err := er.parseInt64(fields[0])
+		if err != nil {
+			return nil, err
+		}
+
+		dest, err := er.parseInt64(fields[1])
+		if err != nil {
+			return nil, err
+		}
+
+		edge.Src = src
+		edge.Dest = dest
+	}
+
+	if len(fields) == 3 {
+		weight, err := er.parseInt32(fields[2])
+		if err != nil {
+			return nil, err
+		}
+		edge.Weight = &weight
+	}
+
+	return edge, nil
+}
+
+func (er *edgeListReader) parseInt64(field string) (int64, error) {
+	v, err := strconv.ParseInt(field, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("line %d: invalid value: %s", er.line, field)
+	}
+	return v, nil
+}
+
+func (er *edgeListReader) parseInt32(field string) (int32, error) {
+	v, err := strconv.ParseInt(field, 10, 32)
+	if err != nil {
+		return 0, fmt.Errorf("line %d: invalid value: %s", er.line, field)
+	}
+	return int32(v), nil
+}
+
+// edgeListWriter prints a graph read back with GetGraph in the format read by edgeListReader, i.e. the total number of
+// vertices followed by one edge per line, with its weight if it has one. A labeled graph is printed as a labeled file
+// instead, i.e. the label of every vertex on its own line, followed by the edges given by labels.
+type edgeListWriter struct {
+	w *bufio.Writer
+	// vertexLabels is the label of every vertex of a labeled graph, in index order
+	vertexLabels []string
+}
+
+func (ew *edgeListWriter) header(id int64, res *pb.GetGraphResponse) {
+	if !res.Labeled {
+		fmt.Fprintln(ew.w, res.TotalVertices)
+	}
+}
+
+func (ew *edgeListWriter) labels(labels []string) {
+	for _, label := range labels {
+		fmt.Fprintln(ew.w, label)
+	}
+	ew.vertexLabels = append(ew.vertexLabels, labels...)
+}
+
+func (ew *edgeListWriter) edges(edges []*pb.Edge) {
+	for _, edge := range edges {
+		var src, dest interface{} = edge.Src, edge.Dest
+		if ew.vertexLabels != nil {
+			src, dest = ew.vertexLabels[edge.Src], ew.vertexLabels[edge.Dest]
+		}
+
+		if edge.Weight != nil {
+			fmt.Fprintln(ew.w, src, dest, *edge.Weight)
+		} else {
+			fmt.Fprintln(ew.w, src, dest)
+		}
+	}
+}
+
+// doPostFile executes the client request, posting the graph read from an edge-list file. The edges are streamed to
+// the server as they are read, so the file is never fully loaded in memory. The total number of vertices is taken
+// from the header of the file, or else from totalVertices, which is -1 if it is not given. The total number of
+// vertices of a labeled graph is given by its labels instead. The coordinates of the vertices, if any, are sent along
+// with the edges, in chunks of the same size.
+func doPostFile(client pb.GraphServiceClient, path string, totalVertices int64, directed bool, labeled bool,
+	coordinates []*pb.Point, heuristic pb.Heuristic) {
+	log.Printf("Posting new graph from %s now...\n", path)
+
+	file, err := os.Open(path)
+	if err != nil {
+		log.Fatalf("Failed to open %s: %v\n", path, err)
+	}
+	defer file.Close()
+
+	er, err := newEdgeListReader(file, labeled)
+	if err != nil {
+		log.Fatalf("Failed to read %s: %v\n", path, err)
+	}
+
+	if er.totalVertices >= 0 && totalVertices >= 0 && er.totalVertices != totalVertices {
+		log.Fatalf("The total number of vertices given as argument (%d) differs from the header of %s (%d)\n",
+			totalVertices, path, er.totalVertices)
+	} else if er.totalVertices >= 0 {
+		totalVertices = er.totalVertices
+	} else if labeled {
+		totalVertices = 0
+	} else if totalVertices < 0 {
+		log.Fatalf("%s has no header, so the total number of vertices must be given as argument\n", path)
+	}
+
+	var readErr error
+	totalEdges := 0
+	header := &pb.PostStreamHeader{
+		TotalVertices: totalVertices,
+		Directed:      directed,
+		Labeled:       labeled,
+		Heuristic:     heuristic,
+	}
+	res, err := postStream(client, header, func() (*pb.EdgeChunk, error) {
+		chunk, size, err := er.readChunk(postStreamChunkBytes)
+		if err == io.EOF && len(coordinates) > 0 {
+			// The coordinates left once the whole file has been read are sent in chunks of their own
+			chunk, size, err = &pb.EdgeChunk{}, 0, nil
+		}
+		if err != nil {
+			if err != io.EOF {
+				readErr = err
+			}
+			return nil, err
+		}
+
+		totalEdges += len(chunk.Edges)
+		chunk.Coordinates = nextCoordinates(&coordinates, size)
+		return chunk, nil
+	})
+
+	if readErr != nil {
+		log.Fatalf("Failed to read %s: %v\n", path, readErr)
+	}
+
+	if labeled {
+		log.Printf("Uploaded %d edges\n", totalEdges)
+	} else {
+		log.Printf("Uploaded %d vertices and %d edges\n", totalVertices, totalEdges)
+	}
+	handlePostResult(res, err)
+}
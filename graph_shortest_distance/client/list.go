@@ -0,0 +1,132 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// doList executes the client request, fetching the graphs page by page until all of them are listed
+func doList(client pb.GraphServiceClient) {
+	log.Println("Listing the graphs now...")
+
+	pageToken := ""
+	total := 0
+	for {
+		res, err := client.ListGraphs(context.Background(), &pb.ListGraphsRequest{PageToken: pageToken})
+
+		// Error handling
+		if err != nil {
+			sts, ok := status.FromError(err)
+
+			if ok {
+				log.Printf("Error message from server: %v\n", sts.Message())
+				log.Printf("Error code: %d\n", sts.Code())
+				log.Fatalf("The graphs could not be listed.\n")
+			} else {
+				log.Fatalf("A non gRPC error: %v\n", err)
+			}
+		}
+
+		for _, info := range res.Graphs {
+			createdAt := "unknown"
+			if info.CreatedAt != nil {
+				createdAt = info.CreatedAt.AsTime().Local().Format(time.RFC3339)
+			}
+
+			preprocessed := "no"
+			if info.Preprocessed {
+				preprocessed = fmt.Sprintf("%d landmarks", info.Landmarks)
+			}
+
+			log.Printf("Graph[id=%d]: %d vertices, %d edges, directed: %v, labeled: %v, created at: %s, "+
+				"preprocessed: %s\n", info.Id, info.TotalVertices, info.TotalEdges, info.Directed, info.Labeled,
+				createdAt, preprocessed)
+		}
+		total += len(res.Graphs)
+
+		if res.NextPageToken == "" {
+			break
+		}
+		pageToken = res.NextPageToken
+	}
+
+	log.Printf("There are %d graphs in the data store.\n", total)
+}
+
+// graphWriter prints a graph read back with GetGraph, as the responses are received
+type graphWriter interface {
+	// header prints the start of the graph, from the first response
+	header(id int64, res *pb.GetGraphResponse)
+	// labels prints the next chunk of vertex labels of a labeled graph, which are all received before the edges
+	labels(labels []string)
+	// edges prints the next chunk of edges
+	edges(edges []*pb.Edge)
+}
+
+// doGet executes the client request. The graph is printed to the standard output in the given format, which is either
+// "edgelist", the same format as the edge-list files of the post method, or "dimacs".
+func doGet(client pb.GraphServiceClient, id int64, format string) {
+	log.Println("Getting the specified graph now...")
+
+	out := bufio.NewWriter(os.Stdout)
+	defer out.Flush()
+
+	var writer graphWriter
+	switch format {
+	case "edgelist":
+		writer = &edgeListWriter{w: out}
+	case "dimacs":
+		writer = &dimacsWriter{w: out}
+	default:
+		log.Fatalf("%s is not a valid format", format)
+	}
+
+	stream, err := client.GetGraph(context.Background(), &pb.GetGraphRequest{Id: id})
+
+	if err != nil {
+		log.Fatalf("Error while calling GetGraph: %v\n", err)
+	}
+
+	for first := true; ; first = false {
+		res, err := stream.Recv()
+
+		if err == io.EOF {
+			break
+		}
+
+		// Error handling
+		if err != nil {
+			sts, ok := status.FromError(err)
+
+			if ok {
+				log.Printf("Error message from server: %v\n", sts.Message())
+				log.Printf("Error code: %d\n", sts.Code())
+
+				if sts.Code() == codes.NotFound {
+					log.Fatalf("Please check if the graph ID is correct.\n")
+				}
+				log.Fatalf("The graph[id=%d] could not be read.\n", id)
+			} else {
+				log.Fatalf("A non gRPC error: %v\n", err)
+			}
+		}
+
+		if first {
+			log.Printf("The graph[id=%d] has %d vertices and %d edges, directed: %v, labeled: %v\n",
+				id, res.TotalVertices, res.TotalEdges, res.Directed, res.Labeled)
+			writer.header(id, res)
+		}
+
+		writer.labels(res.Labels)
+		writer.edges(res.Edges)
+	}
+}
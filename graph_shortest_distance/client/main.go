@@ -2,21 +2,71 @@ package main
 
 import (
 	"flag"
+	"fmt"
 	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials/insecure"
 	"log"
 	"strconv"
+	"strings"
 )
 
 var addr = "0.0.0.0:50051"
 
 func main() {
 	method := flag.String("method", "dist", "Specify one of the following methods to use with the "+
-		"client: post/dist/delete.\n"+
+		"client: post/dist/dist-from/dist-matrix/path/add-edges/remove-edges/add-vertices/preprocess/list/get/export/"+
+		"delete.\n"+
 		"post - post a new graph. The first argument is the total number of vertices, "+
-		"followed by a sequence of node values for representing [src -> dest] pairs.\n"+
-		"dist = compute the shortest distance between two nodes.")
+		"followed by a sequence of node values for representing [src -> dest] pairs. With the -labeled flag, "+
+		"the nodes are given by labels and the total number of vertices is omitted.\n"+
+		"dist = compute the shortest distance between two nodes.\n"+
+		"dist-from = compute the shortest distances from one node to every node reachable from it. The arguments "+
+		"are the graph ID and the source node.\n"+
+		"dist-matrix = compute the shortest distance between every source node and every destination node. The "+
+		"arguments are the graph ID, the comma-separated source nodes and the comma-separated destination nodes.\n"+
+		"path = compute one shortest path between two nodes.\n"+
+		"add-edges = add edges to an existing graph. The first argument is the graph ID, followed by the edges "+
+		"given the same way as with the post method.\n"+
+		"remove-edges = remove edges from an existing graph, with the same arguments as the add-edges method.\n"+
+		"add-vertices = add vertices to an existing graph. The arguments are the graph ID and the number of "+
+		"vertices to add, or the labels of the vertices to add with the -labeled flag.\n"+
+		"preprocess = pick landmarks in a graph, which speed up the subsequent dist queries on it. The argument is "+
+		"the graph ID.\n"+
+		"list = list the ID, size, creation time and preprocessing status of all the graphs.\n"+
+		"get = print the vertices and edges of a graph.\n"+
+		"export = write a graph to a GraphML or DOT file. The first argument is the graph ID, optionally followed "+
+		"by two nodes whose shortest path is highlighted.")
+	weighted := flag.Bool("weighted", false, "Used with the post, add-edges and remove-edges methods. "+
+		"When set, each edge is given as a [src dest weight] triple instead of a [src -> dest] pair.")
+	directed := flag.Bool("directed", false, "Used with the post method. When set, the edges of the graph "+
+		"can only be traversed from src to dest.")
+	labeled := flag.Bool("labeled", false, "When set, the nodes are given by string labels instead of indexes, "+
+		"for the post method (including with the -file flag) and for the methods querying or modifying a labeled "+
+		"graph. The graph IDs are still numeral.")
+	file := flag.String("file", "", "Used with the post method. The path of an edge-list file to post the graph "+
+		"from, holding one [src dest] or [src dest weight] edge per line, separated by whitespace or commas. "+
+		"Everything after a # is a comment, and the first line may hold the total number of vertices. "+
+		"Otherwise, the total number of vertices is given as the only argument. With the -labeled flag, the "+
+		"nodes are given by labels, and a line holding a single label adds a vertex.")
+	format := flag.String("format", "", "Used with the post method along with the -file flag, and with the get "+
+		"and export methods. The format of the graph file. The post and get methods accept edgelist (default) or "+
+		"dimacs (the shortest path format of the 9th DIMACS Implementation Challenge). The export method accepts "+
+		"graphml (default) or dot.")
+	output := flag.String("output", "", "Used with the export method. The path of the file to write, which "+
+		"defaults to graph<id> followed by the extension of the format in the current directory.")
+	maxDistance := flag.Int64("max-distance", -1, "Used with the dist-from method. When not negative, only the "+
+		"nodes whose shortest distance is at most max-distance are printed.")
+	coordinatesFile := flag.String("coordinates", "", "Used with the post method, except with DIMACS files. The "+
+		"path of a file holding the [x y] coordinates of one vertex per line, in the order of the vertex indexes, "+
+		"so that the shortest distances are computed by A* search.")
+	heuristic := flag.String("heuristic", "", "Used with the post method along with the -coordinates flag. The "+
+		"heuristic of A* search, either euclidean (default) or manhattan.")
+	landmarks := flag.Int("landmarks", 0, "Used with the preprocess method. The number of landmarks to pick, "+
+		"which defaults to the server's choice if it is 0.")
+	timeout := flag.Duration("timeout", 0, "Used with the dist, dist-from, dist-matrix, path and preprocess "+
+		"methods. The maximum time to wait for the shortest distances to be computed, e.g. 500ms or 10s. No timeout "+
+		"is applied if it is 0.")
 
 	conn, err := grpc.Dial(addr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 
@@ -33,37 +83,76 @@ func main() {
 
 	switch *method {
 	case "post":
-		// Parse the inputs
-		if len(args) < 1 {
-			log.Fatalln("Insufficient number of arguments")
-		} else if (len(args)-1)%2 != 0 {
-			log.Fatalln("Make sure the number of values to represent the edges is even (in pairs)")
+		var coordinates []*pb.Point
+		if *coordinatesFile != "" {
+			if *file != "" && *format == "dimacs" {
+				log.Fatalf("The coordinates cannot be given along with a DIMACS file\n")
+			}
+
+			coordinates, err = readCoordinates(*coordinatesFile)
+			if err != nil {
+				log.Fatalf("Failed to read %s: %v\n", *coordinatesFile, err)
+			}
 		}
 
-		totalVertices, err := strconv.ParseInt(args[0], 10, 32)
+		heuristicPb, err := parseHeuristic(*heuristic)
 		if err != nil {
-			log.Fatalf("Invalid input: %s\n", args[0])
+			log.Fatalln(err)
 		}
 
-		var edgesRaw = make([][2]int32, (len(args)-1)/2)
-		for i := 1; i < len(args); i++ {
-			src, err := strconv.ParseInt(args[i], 10, 32)
-			if err != nil {
-				log.Fatalf("Invalid input: %s\n", args[i])
+		if *file != "" {
+			// Parse the inputs
+			if len(args) > 1 {
+				log.Fatalf("The [post] method accepts at most 1 numeral argument with the -file flag\n")
 			}
-			i++
 
-			dest, err := strconv.ParseInt(args[i], 10, 32)
-			if err != nil {
-				log.Fatalf("Invalid input: %s\n", args[i])
+			totalVertices := int64(-1)
+			if len(args) == 1 {
+				totalVertices, err = strconv.ParseInt(args[0], 10, 64)
+				if err != nil || totalVertices < 0 {
+					log.Fatalf("Invalid input: %s\n", args[0])
+				}
+			}
+
+			if *labeled && len(args) != 0 {
+				log.Fatalf("The total number of vertices of a labeled graph is given by its labels\n")
 			}
 
-			edgesRaw[i/2-1][0] = int32(src)
-			edgesRaw[i/2-1][1] = int32(dest)
+			if *format == "dimacs" {
+				if len(args) != 0 || *directed || *labeled {
+					log.Fatalf("The total number of vertices and the direction of the edges are given by the " +
+						"DIMACS file itself, whose vertices are not labeled\n")
+				}
+
+				doImportFile(client, *file)
+			} else if *format == "" || *format == "edgelist" {
+				doPostFile(client, *file, totalVertices, *directed, *labeled, coordinates, heuristicPb)
+			} else {
+				log.Fatalf("%s is not a valid format", *format)
+			}
+			break
+		}
+
+		// Parse the inputs
+		if *labeled {
+			// The total number of vertices is given by the labels
+			doPost(client, 0, parseEdges(args, *weighted, true), *directed, true, coordinates, heuristicPb)
+			break
 		}
 
+		if len(args) < 1 {
+			log.Fatalln("Insufficient number of arguments")
+		}
+
+		totalVertices, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid input: %s\n", args[0])
+		}
+
+		edgesPb := parseEdges(args[1:], *weighted, false)
+
 		// Do the posting action
-		doPost(client, int32(totalVertices), edgesRaw)
+		doPost(client, totalVertices, edgesPb, *directed, false, coordinates, heuristicPb)
 	case "dist":
 		// Parse the inputs
 		if len(args) > 3 {
@@ -72,65 +161,260 @@ func main() {
 					"Arguments must be given as pairs of 3 consecutive numerical values.")
 			}
 
-			var ids = make([]int32, len(args)/3)
-			var srcs = make([]int32, len(args)/3)
-			var dests = make([]int32, len(args)/3)
+			var ids = make([]int64, len(args)/3)
+			var srcs = make([]node, len(args)/3)
+			var dests = make([]node, len(args)/3)
 			for i := 0; i < len(args); i += 3 {
-				id, err := strconv.ParseInt(args[i], 10, 32)
+				id, err := strconv.ParseInt(args[i], 10, 64)
 				if err != nil {
 					log.Fatalf("Invalid input: %s\n", args[i])
 				}
 
-				src, err := strconv.ParseInt(args[i+1], 10, 32)
-				if err != nil {
-					log.Fatalf("Invalid input: %s\n", args[i+1])
-				}
-
-				dest, err := strconv.ParseInt(args[i+2], 10, 32)
-				if err != nil {
-					log.Fatalf("Invalid input: %s\n", args[i+2])
-				}
-
-				ids[i/3] = int32(id)
-				srcs[i/3] = int32(src)
-				dests[i/3] = int32(dest)
+				ids[i/3] = id
+				srcs[i/3] = parseNode(args[i+1], *labeled)
+				dests[i/3] = parseNode(args[i+2], *labeled)
 			}
 
-			doDistStream(client, ids, srcs, dests)
+			doDistStream(client, ids, srcs, dests, *timeout)
 		} else if len(args) == 3 {
-			id, err := strconv.ParseInt(args[0], 10, 32)
+			id, err := strconv.ParseInt(args[0], 10, 64)
 			if err != nil {
 				log.Fatalf("Invalid input: %s\n", args[0])
 			}
 
-			src, err := strconv.ParseInt(args[1], 10, 32)
-			if err != nil {
-				log.Fatalf("Invalid input: %s\n", args[1])
-			}
+			doDist(client, id, parseNode(args[1], *labeled), parseNode(args[2], *labeled), *timeout)
+		} else {
+			log.Fatalf("The [dist] method accepts 3 or more numeral arguments\n")
+		}
+	case "dist-from":
+		// Parse the inputs
+		if len(args) != 2 {
+			log.Fatalf("The [dist-from] method accepts 2 numeral arguments exactly\n")
+		}
 
-			dest, err := strconv.ParseInt(args[2], 10, 32)
-			if err != nil {
-				log.Fatalf("Invalid input: %s\n", args[2])
-			}
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid input: %s\n", args[0])
+		}
+
+		doDistFrom(client, id, parseNode(args[1], *labeled), *maxDistance, *timeout)
+	case "dist-matrix":
+		// Parse the inputs
+		if len(args) != 3 {
+			log.Fatalf("The [dist-matrix] method accepts 3 arguments exactly\n")
+		}
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid input: %s\n", args[0])
+		}
+
+		doDistMatrix(client, id, parseNodeList(args[1], *labeled), parseNodeList(args[2], *labeled), *timeout)
+	case "path":
+		// Parse the inputs
+		if len(args) != 3 {
+			log.Fatalf("The [path] method accepts 3 numeral arguments exactly\n")
+		}
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid input: %s\n", args[0])
+		}
+
+		doPath(client, id, parseNode(args[1], *labeled), parseNode(args[2], *labeled), *timeout)
+	case "add-edges", "remove-edges":
+		// Parse the inputs
+		if len(args) < 1 {
+			log.Fatalln("Insufficient number of arguments")
+		}
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid input: %s\n", args[0])
+		}
 
-			doDist(client, int32(id), int32(src), int32(dest))
+		edgesPb := parseEdges(args[1:], *weighted, *labeled)
+
+		if *method == "add-edges" {
+			doAddEdges(client, id, edgesPb)
 		} else {
-			log.Fatalf("The [dist] method accepts 3 or more numeral arguments\n")
+			doRemoveEdges(client, id, edgesPb)
+		}
+	case "add-vertices":
+		// Parse the inputs
+		if *labeled && len(args) < 2 {
+			log.Fatalf("The [add-vertices] method accepts the graph ID followed by 1 or more labels\n")
+		} else if !*labeled && len(args) != 2 {
+			log.Fatalf("The [add-vertices] method accepts 2 numeral arguments exactly\n")
 		}
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid input: %s\n", args[0])
+		}
+
+		if *labeled {
+			doAddVertices(client, id, int64(len(args)-1), args[1:])
+			break
+		}
+
+		count, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid input: %s\n", args[1])
+		}
+
+		doAddVertices(client, id, count, nil)
+	case "list":
+		if len(args) != 0 {
+			log.Fatalf("The [list] method accepts no argument\n")
+		}
+
+		doList(client)
+	case "get":
+		// Parse the inputs
+		if len(args) != 1 {
+			log.Fatalf("The [get] method accepts 1 numeral argument exactly\n")
+		}
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid input: %s\n", args[0])
+		}
+
+		if *format == "" {
+			*format = "edgelist"
+		}
+
+		doGet(client, id, *format)
+	case "export":
+		// Parse the inputs
+		if len(args) != 1 && len(args) != 3 {
+			log.Fatalf("The [export] method accepts 1 or 3 numeral arguments\n")
+		}
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid input: %s\n", args[0])
+		}
+
+		var highlight *pb.PathHighlight
+		if len(args) == 3 {
+			src := parseNode(args[1], *labeled)
+			dest := parseNode(args[2], *labeled)
+			highlight = &pb.PathHighlight{Src: src.index, Dest: dest.index, SrcLabel: src.label, DestLabel: dest.label}
+		}
+
+		if *format == "" {
+			*format = "graphml"
+		}
+		exportFormat, ok := exportFormats[*format]
+		if !ok {
+			log.Fatalf("%s is not a valid export format", *format)
+		}
+
+		if *output == "" {
+			*output = fmt.Sprintf("graph%d%s", id, exportFormat.ext)
+		}
+
+		doExport(client, id, exportFormat.format, highlight, *output)
+	case "preprocess":
+		// Parse the inputs
+		if len(args) != 1 {
+			log.Fatalf("The [preprocess] method accepts 1 numeral argument exactly\n")
+		}
+
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			log.Fatalf("Invalid input: %s\n", args[0])
+		}
+
+		doPreprocess(client, id, int32(*landmarks), *timeout)
 	case "delete":
 		// Parse the inputs
 		if len(args) != 1 {
 			log.Fatalf("The [delete] method accepts 1 numeral argument exactly\n")
 		} else {
-			id, err := strconv.ParseInt(args[0], 10, 32)
+			id, err := strconv.ParseInt(args[0], 10, 64)
 			if err != nil {
 				log.Fatalf("Invalid input: %s\n", args[0])
 			}
 
-			doDelete(client, int32(id))
+			doDelete(client, id)
 		}
 
 	default:
 		log.Fatalf("%s is not a valid method", *method)
 	}
 }
+
+// node is a node given as argument, either by its index or by its label in a labeled graph
+type node struct {
+	index int64
+	label string
+}
+
+func (n node) String() string {
+	if n.label != "" {
+		return n.label
+	}
+	return strconv.FormatInt(n.index, 10)
+}
+
+// parseNode parses a node given as argument, which is a label if labeled is set, and an index otherwise
+func parseNode(arg string, labeled bool) node {
+	if labeled {
+		return node{label: arg}
+	}
+
+	index, err := strconv.ParseInt(arg, 10, 64)
+	if err != nil {
+		log.Fatalf("Invalid input: %s\n", arg)
+	}
+	return node{index: index}
+}
+
+// parseNodeList parses a comma-separated list of nodes given as argument
+func parseNodeList(arg string, labeled bool) []node {
+	var nodes []node
+	for _, field := range strings.Split(arg, ",") {
+		nodes = append(nodes, parseNode(field, labeled))
+	}
+	return nodes
+}
+
+// parseEdges parses the nodes representing the edges as [src -> dest] pairs, or as [src dest weight] triples if the
+// edges are weighted. The nodes are given by their labels if labeled is set, and by their indexes otherwise.
+func parseEdges(args []string, weighted bool, labeled bool) []*pb.Edge {
+	edgeArgs := 2
+	if weighted {
+		edgeArgs = 3
+	}
+
+	if weighted && len(args)%edgeArgs != 0 {
+		log.Fatalln("Make sure the number of values to represent the weighted edges is a multiple of 3 " +
+			"(in triples)")
+	} else if len(args)%edgeArgs != 0 {
+		log.Fatalln("Make sure the number of values to represent the edges is even (in pairs)")
+	}
+
+	var edgesPb = make([]*pb.Edge, len(args)/edgeArgs)
+	for i := 0; i < len(args); i += edgeArgs {
+		src := parseNode(args[i], labeled)
+		dest := parseNode(args[i+1], labeled)
+		edge := &pb.Edge{Src: src.index, Dest: dest.index, SrcLabel: src.label, DestLabel: dest.label}
+
+		if weighted {
+			weight, err := strconv.ParseInt(args[i+2], 10, 32)
+			if err != nil {
+				log.Fatalf("Invalid input: %s\n", args[i+2])
+			}
+
+			w := int32(weight)
+			edge.Weight = &w
+		}
+
+		edgesPb[i/edgeArgs] = edge
+	}
+
+	return edgesPb
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// doAddEdges executes the client request. The nodes of the edges of a labeled graph are given by their labels, and
+// the labels which do not exist yet are added as new vertices.
+func doAddEdges(client pb.GraphServiceClient, id int64, edgesPb []*pb.Edge) {
+	log.Println("Adding edges to the graph now...")
+
+	res, err := client.AddEdges(context.Background(), &pb.AddEdgesRequest{
+		Id:    id,
+		Edges: edgesPb,
+	})
+
+	handleMutationResult(id, res, err)
+}
+
+// doRemoveEdges executes the client request. The weights are optional, and if an edge has one, only the edges of the
+// graph having the same weight are removed.
+func doRemoveEdges(client pb.GraphServiceClient, id int64, edgesPb []*pb.Edge) {
+	log.Println("Removing edges from the graph now...")
+
+	res, err := client.RemoveEdges(context.Background(), &pb.RemoveEdgesRequest{
+		Id:    id,
+		Edges: edgesPb,
+	})
+
+	handleMutationResult(id, res, err)
+}
+
+// doAddVertices executes the client request. The vertices added to a labeled graph are given by their labels.
+func doAddVertices(client pb.GraphServiceClient, id int64, count int64, labels []string) {
+	log.Println("Adding vertices to the graph now...")
+
+	res, err := client.AddVertices(context.Background(), &pb.AddVerticesRequest{
+		Id:     id,
+		Count:  count,
+		Labels: labels,
+	})
+
+	handleMutationResult(id, res, err)
+}
+
+// handleMutationResult reports the outcome of a mutation of the graph
+func handleMutationResult(id int64, res *pb.MutationResponse, err error) {
+	// Error handling
+	if err != nil {
+		sts, ok := status.FromError(err)
+
+		if ok {
+			log.Printf("Error message from server: %v\n", sts.Message())
+			log.Printf("Error code: %d\n", sts.Code())
+
+			if sts.Code() == codes.InvalidArgument {
+				log.Fatalf("Please check if the node values and weights representing the edges are all valid.\n")
+			} else if sts.Code() == codes.NotFound {
+				log.Fatalf("Please check if the graph ID and the edges to remove are correct.\n")
+			}
+			log.Fatalf("The graph[id=%d] could not be modified.\n", id)
+		} else {
+			log.Fatalf("A non gRPC error: %v\n", err)
+		}
+	}
+
+	log.Printf("The graph[id=%d] is successfully modified. It now has %d vertices and %d edges.\n",
+		id, res.TotalVertices, res.TotalEdges)
+}
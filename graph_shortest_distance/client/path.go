@@ -0,0 +1,60 @@
+package main
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"time"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// doPath executes the client request. The request is given up once the timeout elapses, unless the timeout is 0.
+func doPath(client pb.GraphServiceClient, id int64, src node, dest node, timeout time.Duration) {
+	log.Println("Computing shortest path now...")
+
+	ctx, cancel := requestContext(timeout)
+	defer cancel()
+
+	res, err := client.Path(ctx, &pb.DistRequest{
+		Id:        id,
+		Src:       src.index,
+		Dest:      dest.index,
+		SrcLabel:  src.label,
+		DestLabel: dest.label,
+	})
+
+	// Error handling
+	if err != nil {
+		sts, ok := status.FromError(err)
+
+		if ok {
+			log.Printf("Error message from server: %v\n", sts.Message())
+			log.Printf("Error code: %d\n", sts.Code())
+
+			if sts.Code() == codes.InvalidArgument {
+				log.Fatalf("Please check if the specified source node or destination node exist in the graph.\n")
+			} else if sts.Code() == codes.NotFound {
+				log.Fatalf("Please check if the graph ID is correct.\n")
+			} else if sts.Code() == codes.DeadlineExceeded {
+				log.Fatalf("The shortest path could not be computed within the timeout of %v.\n", timeout)
+			}
+		} else {
+			log.Fatalf("A non gRPC error: %v\n", err)
+		}
+	}
+
+	if !res.Reachable {
+		log.Printf("The source node [%v] and destination node [%v] in graph[id=%d] are not connected.\n",
+			src, dest, id)
+	} else {
+		// The path of a labeled graph is printed with the labels of its vertices
+		var vertices interface{} = res.Vertices
+		if len(res.VertexLabels) > 0 {
+			vertices = res.VertexLabels
+		}
+
+		log.Printf("The shortest path between node [%v] and node [%v] in graph[id=%d] is: %v (distance: %d)\n",
+			src, dest, id, vertices, res.Result)
+	}
+}
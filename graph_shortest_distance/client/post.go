@@ -4,26 +4,98 @@ import (
 	"context"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"io"
 	"log"
 
 	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
 )
 
-// doPost executes the client request
-func doPost(client pb.GraphServiceClient, totalVertices int32, edgesRaw [][2]int32) {
+// postStreamChunkBytes is the encoded size above which a graph is uploaded with PostStream instead of Post, and up to
+// which every message of PostStream is filled. The messages are sized by bytes rather than by number of edges, since
+// the labels make the size of an edge unbounded, so that no message comes close to the default gRPC message size
+// limit of 4 MiB.
+const postStreamChunkBytes = 1 << 20
+
+// doPost executes the client request. The nodes of the edges of a labeled graph are given by their labels, and its
+// total number of vertices must be 0. The coordinates of the vertices, if any, are posted along with the heuristic.
+// Graphs whose request would exceed postStreamChunkBytes are uploaded in chunks over a stream.
+func doPost(client pb.GraphServiceClient, totalVertices int64, edgesPb []*pb.Edge, directed bool, labeled bool,
+	coordinates []*pb.Point, heuristic pb.Heuristic) {
 	log.Println("Posting new graph now...")
 
-	edgesPb := make([]*pb.Edge, len(edgesRaw))
+	req := &pb.PostRequest{
+		TotalVertices: totalVertices,
+		Edges:         edgesPb,
+		Directed:      directed,
+		Coordinates:   coordinates,
+		Heuristic:     heuristic,
+	}
+
+	var res *pb.PostResponse
+	var err error
+	if proto.Size(req) > postStreamChunkBytes {
+		log.Printf("Uploading %d edges in chunks of %d bytes...\n", len(edgesPb), postStreamChunkBytes)
+
+		header := &pb.PostStreamHeader{
+			TotalVertices: totalVertices,
+			Directed:      directed,
+			Labeled:       labeled,
+			Heuristic:     heuristic,
+		}
+		res, err = postStream(client, header, func() (*pb.EdgeChunk, error) {
+			if len(edgesPb) == 0 && len(coordinates) == 0 {
+				return nil, io.EOF
+			}
+
+			size := 0
+			end := 0
+			for end < len(edgesPb) && (end == 0 || size < postStreamChunkBytes) {
+				size += messageFieldSize(edgesPb[end])
+				end++
+			}
 
-	for i := 0; i < len(edgesRaw); i++ {
-		edgesPb[i] = &pb.Edge{Src: edgesRaw[i][0], Dest: edgesRaw[i][1]}
+			chunk := &pb.EdgeChunk{Edges: edgesPb[:end]}
+			edgesPb = edgesPb[end:]
+			chunk.Coordinates = nextCoordinates(&coordinates, size)
+			return chunk, nil
+		})
+	} else {
+		res, err = client.Post(context.Background(), req)
 	}
 
-	res, err := client.Post(context.Background(), &pb.PostRequest{
-		TotalVertices: totalVertices,
-		Edges:         edgesPb,
-	})
+	handlePostResult(res, err)
+}
+
+// nextCoordinates removes from the coordinates left to send those filling the next chunk of PostStream, whose edges
+// and labels already take size bytes, up to postStreamChunkBytes, and returns them
+func nextCoordinates(coordinates *[]*pb.Point, size int) []*pb.Point {
+	end := 0
+	for end < len(*coordinates) && size < postStreamChunkBytes {
+		size += messageFieldSize((*coordinates)[end])
+		end++
+	}
+
+	next := (*coordinates)[:end]
+	*coordinates = (*coordinates)[end:]
+	return next
+}
+
+// messageFieldSize returns the encoded size of a message as an element of a repeated field, whose field number is
+// assumed to be below 16 so that its tag takes a single byte
+func messageFieldSize(m proto.Message) int {
+	return 1 + protowire.SizeBytes(proto.Size(m))
+}
+
+// stringFieldSize returns the encoded size of a string as an element of a repeated field, the same as
+// messageFieldSize
+func stringFieldSize(s string) int {
+	return 1 + protowire.SizeBytes(len(s))
+}
 
+// handlePostResult reports the outcome of posting a graph
+func handlePostResult(res *pb.PostResponse, err error) {
 	// Error handling
 	if err != nil {
 		sts, ok := status.FromError(err)
@@ -33,13 +105,49 @@ func doPost(client pb.GraphServiceClient, totalVertices int32, edgesRaw [][2]int
 			log.Printf("Error code: %d\n", sts.Code())
 
 			if sts.Code() == codes.InvalidArgument {
-				log.Fatalf("Please check if the node values representing the edges are all valid.\n")
+				log.Fatalf("Please check if the node values and weights representing the edges, as well as the " +
+					"coordinates of the vertices, are all valid.\n")
 			}
+			log.Fatalf("The graph could not be posted.\n")
 		} else {
 			log.Fatalf("A non gRPC error: %v\n", err)
 		}
-
 	}
 
 	log.Printf("New graph posted to server successfully. Graph ID: %d\n", res.Result)
 }
+
+// postStream uploads a graph with PostStream, sending the header followed by the chunks of edges and vertex labels
+// returned by nextChunk, until it returns io.EOF. Any other error from nextChunk cancels the upload, so nothing is
+// saved.
+func postStream(client pb.GraphServiceClient, header *pb.PostStreamHeader,
+	nextChunk func() (*pb.EdgeChunk, error)) (*pb.PostResponse, error) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	stream, err := client.PostStream(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	err = stream.Send(&pb.PostStreamRequest{Data: &pb.PostStreamRequest_Header{Header: header}})
+
+	for err == nil {
+		chunk, chunkErr := nextChunk()
+		if chunkErr == io.EOF {
+			break
+		}
+		if chunkErr != nil {
+			return nil, chunkErr
+		}
+
+		err = stream.Send(&pb.PostStreamRequest{Data: &pb.PostStreamRequest_Edges{Edges: chunk}})
+	}
+
+	// Sending fails with io.EOF once the server ended the stream, whose actual error is then returned by CloseAndRecv
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+
+	return stream.CloseAndRecv()
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"time"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// doPreprocess executes the client request. The server picks its default number of landmarks if landmarks is 0. The
+// request is given up once the timeout elapses, unless the timeout is 0.
+func doPreprocess(client pb.GraphServiceClient, id int64, landmarks int32, timeout time.Duration) {
+	log.Println("Preprocessing the specified graph now...")
+
+	ctx, cancel := requestContext(timeout)
+	defer cancel()
+
+	res, err := client.Preprocess(ctx, &pb.PreprocessRequest{Id: id, Landmarks: landmarks})
+
+	// Error handling
+	if err != nil {
+		sts, ok := status.FromError(err)
+
+		if ok {
+			log.Printf("Error message from server: %v\n", sts.Message())
+			log.Printf("Error code: %d\n", sts.Code())
+
+			if sts.Code() == codes.InvalidArgument {
+				log.Fatalf("Please check if the number of landmarks is valid.\n")
+			} else if sts.Code() == codes.NotFound {
+				log.Fatalf("Please check if the graph ID is correct.\n")
+			} else if sts.Code() == codes.DeadlineExceeded {
+				log.Fatalf("The graph could not be preprocessed within the timeout of %v.\n", timeout)
+			} else if sts.Code() == codes.Aborted {
+				log.Fatalf("The graph was modified while being preprocessed. Please try again.\n")
+			}
+			log.Fatalf("The graph could not be preprocessed.\n")
+		} else {
+			log.Fatalf("A non gRPC error: %v\n", err)
+		}
+	}
+
+	picked := make([]node, len(res.Landmarks))
+	for i, vertex := range res.Landmarks {
+		picked[i].index = vertex
+		if len(res.LandmarkLabels) > 0 {
+			picked[i].label = res.LandmarkLabels[i]
+		}
+	}
+
+	log.Printf("The graph[id=%d] is preprocessed with %d landmarks: %v\n", id, len(picked), picked)
+}
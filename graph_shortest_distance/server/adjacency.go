@@ -0,0 +1,69 @@
+package main
+
+import (
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// adjacency is the adjacency structure of a graph in compressed sparse row (CSR) form, which is built once when the
+// graph is created and then shared by all the queries on it.
+// The neighbours of the ith vertex are targets[offsets[i]:offsets[i+1]], and for weighted graphs, the weights of
+// the corresponding edges are weights[offsets[i]:offsets[i+1]].
+type adjacency struct {
+	offsets []int64
+	targets []int64
+	weights []int32
+}
+
+// buildAdjacency builds the adjacency structure of a graph. Unless the graph is directed, every edge is added in both
+// directions. The weights are only kept for weighted graphs.
+func buildAdjacency(totalVertices int64, edges []*pb.Edge, directed bool, weighted bool) adjacency {
+	// Count the out-degree of every vertex, shifted by one so the prefix sums turn into the offsets
+	offsets := make([]int64, totalVertices+1)
+	for _, edge := range edges {
+		offsets[edge.Src+1]++
+		if !directed {
+			offsets[edge.Dest+1]++
+		}
+	}
+	for i := int64(1); i <= totalVertices; i++ {
+		offsets[i] += offsets[i-1]
+	}
+
+	adj := adjacency{
+		offsets: offsets,
+		targets: make([]int64, offsets[totalVertices]),
+	}
+	if weighted {
+		adj.weights = make([]int32, offsets[totalVertices])
+	}
+
+	// next[i] is the position where the next neighbour of the ith vertex goes
+	next := make([]int64, totalVertices)
+	copy(next, offsets)
+	add := func(src int64, dest int64, weight int32) {
+		adj.targets[next[src]] = dest
+		if weighted {
+			adj.weights[next[src]] = weight
+		}
+		next[src]++
+	}
+
+	for _, edge := range edges {
+		add(edge.Src, edge.Dest, edgeWeight(edge))
+		if !directed {
+			add(edge.Dest, edge.Src, edgeWeight(edge))
+		}
+	}
+
+	return adj
+}
+
+// neighbours returns the vertices adjacent to the given vertex
+func (adj adjacency) neighbours(node int64) []int64 {
+	return adj.targets[adj.offsets[node]:adj.offsets[node+1]]
+}
+
+// neighbourWeights returns the weights of the edges leading to the vertices returned by neighbours
+func (adj adjacency) neighbourWeights(node int64) []int32 {
+	return adj.weights[adj.offsets[node]:adj.offsets[node+1]]
+}
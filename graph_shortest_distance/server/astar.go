@@ -0,0 +1,112 @@
+package main
+
+import (
+	"container/heap"
+	"context"
+	"math"
+)
+
+// estimator returns a lower bound of the shortest distance from the vertex to the destination node of a search, and
+// false if the destination node is certainly not reachable from the vertex
+type estimator func(node int64) (int64, bool)
+
+// graphEstimator returns the estimator of the shortest distances to the destination node of the graph, which is the
+// best of the bounds given by the vertex coordinates and by the landmarks of a preprocessed graph. Both bounds are
+// consistent, and so is the largest of them.
+func graphEstimator(graph Graph, dest int64) estimator {
+	return func(node int64) (int64, bool) {
+		var estimate int64
+		if graph.landmarks != nil {
+			bound, reachable := graph.landmarks.lowerBound(node, dest)
+			if !reachable {
+				return 0, false
+			}
+			estimate = bound
+		}
+		if graph.coordinates != nil {
+			if bound := graph.coordinates.estimate(node, dest); bound > estimate {
+				estimate = bound
+			}
+		}
+		return estimate, true
+	}
+}
+
+// getAStarDistance takes the total number of vertices, the source node, the destination node, the adjacency structure
+// as well as the estimator of the distances to the destination node, and returns the shortest distance between those
+// two nodes, or math.MaxInt64 if they are not connected, along with the parent pointers recorded during the search.
+// The search is aborted with an error once the context is done.
+// The function uses A* search, which is Dijkstra's algorithm visiting the vertices in order of their distance to the
+// source node plus the estimate of their distance to the destination node, so that the vertices leading away from the
+// destination node are mostly left unvisited, and the vertices which cannot reach it are never visited. The estimates
+// are consistent, so the shortest distance found is the same as the one of Dijkstra's algorithm. The edges of an
+// unweighted graph have unit weight.
+func getAStarDistance(ctx context.Context, totalVertices int64, src int64, dest int64, adj adjacency,
+	estimate estimator) (int64, []int64, error) {
+	srcEstimate, reachable := estimate(src)
+	if !reachable {
+		return math.MaxInt64, nil, nil
+	}
+
+	// The dist list records the shortest distance found so far of each vertex to the source node
+	dist := make([]int64, totalVertices)
+	for i := 0; i < len(dist); i++ {
+		dist[i] = math.MaxInt64
+	}
+
+	// parent[i] is the vertex through which the ith vertex is reached with the shortest distance found so far
+	parent := make([]int64, totalVertices)
+
+	// settled[] stores whether the shortest distance of the ith vertex is final
+	settled := make([]bool, totalVertices)
+
+	// The queue is ordered by the distance to the source node plus the estimate, rather than the distance alone
+	dist[src] = 0
+	pq := &distQueue{{node: src, dist: srcEstimate}}
+
+	// A* search
+	for polled := 1; pq.Len() != 0; polled++ {
+		if polled%cancellationCheckInterval == 0 {
+			if err := checkCancellation(ctx); err != nil {
+				return 0, nil, err
+			}
+		}
+
+		item := heap.Pop(pq).(distItem)
+		if settled[item.node] {
+			continue
+		}
+		settled[item.node] = true
+
+		if item.node == dest {
+			break
+		}
+
+		var weights []int32
+		if adj.weights != nil {
+			weights = adj.neighbourWeights(item.node)
+		}
+		for i, neighbour := range adj.neighbours(item.node) {
+			weight := int64(1)
+			if weights != nil {
+				weight = int64(weights[i])
+			}
+
+			newDist := dist[item.node] + weight
+			if settled[neighbour] || newDist >= dist[neighbour] {
+				continue
+			}
+
+			neighbourEstimate, reachable := estimate(neighbour)
+			if !reachable {
+				continue
+			}
+
+			dist[neighbour] = newDist
+			parent[neighbour] = item.node
+			heap.Push(pq, distItem{node: neighbour, dist: newDist + neighbourEstimate})
+		}
+	}
+
+	return dist[dest], parent, nil
+}
@@ -0,0 +1,362 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"math"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// gridGraph generates a width x height grid, whose vertices are numbered row by row, along with the coordinates of
+// its vertices. Every edge connects two neighbouring vertices, and has a random weight of at least 1 if weighted.
+func gridGraph(width int64, height int64, weighted bool, seed int64) ([]*pb.Edge, []*pb.Point) {
+	r := rand.New(rand.NewSource(seed))
+
+	var edges []*pb.Edge
+	coordinates := make([]*pb.Point, 0, width*height)
+	for y := int64(0); y < height; y++ {
+		for x := int64(0); x < width; x++ {
+			node := y*width + x
+			coordinates = append(coordinates, &pb.Point{X: float64(x), Y: float64(y)})
+
+			if x+1 < width {
+				edges = append(edges, &pb.Edge{Src: node, Dest: node + 1})
+			}
+			if y+1 < height {
+				edges = append(edges, &pb.Edge{Src: node, Dest: node + width})
+			}
+		}
+	}
+
+	if weighted {
+		for _, edge := range edges {
+			edge.Weight = proto.Int32(1 + r.Int31n(9))
+		}
+	}
+	return edges, coordinates
+}
+
+// TestServer_AStar tests for querying the shortest distances of graphs whose vertices have coordinates
+func TestServer_AStar(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	// The direct edge from 0 to 3 is far heavier than the detour through 1 and 2
+	graphs := []*pb.PostRequest{
+		{
+			TotalVertices: 5,
+			Edges: []*pb.Edge{
+				{Src: 0, Dest: 3, Weight: proto.Int32(10)},
+				{Src: 0, Dest: 1, Weight: proto.Int32(2)},
+				{Src: 1, Dest: 2, Weight: proto.Int32(2)},
+				{Src: 2, Dest: 3, Weight: proto.Int32(2)},
+			},
+			Coordinates: []*pb.Point{{X: 0, Y: 0}, {X: 0, Y: 1}, {X: 1, Y: 1}, {X: 1, Y: 0}, {X: 5, Y: 5}},
+		},
+		{
+			Edges: []*pb.Edge{
+				{SrcLabel: "a", DestLabel: "b"},
+				{SrcLabel: "b", DestLabel: "c"},
+			},
+			Directed:    true,
+			Coordinates: []*pb.Point{{X: 0, Y: 0}, {X: 3, Y: 4}, {X: 6, Y: 8}},
+			Heuristic:   pb.Heuristic_HEURISTIC_MANHATTAN,
+		},
+	}
+
+	for _, graph := range graphs {
+		if _, err := client.Post(context.Background(), graph); err != nil {
+			t.Fatalf("Post(%+v) got unexpected error: %v", graph, err)
+		}
+	}
+
+	tests := []struct {
+		req       *pb.DistRequest
+		expected  int64
+		reachable bool
+	}{
+		{req: &pb.DistRequest{Id: 0, Src: 0, Dest: 3}, expected: 6, reachable: true},
+		{req: &pb.DistRequest{Id: 0, Src: 3, Dest: 0}, expected: 6, reachable: true},
+		{req: &pb.DistRequest{Id: 0, Src: 1, Dest: 1}, expected: 0, reachable: true},
+		{req: &pb.DistRequest{Id: 0, Src: 0, Dest: 4}, expected: 0, reachable: false},
+		{req: &pb.DistRequest{Id: 1, SrcLabel: "a", DestLabel: "c"}, expected: 2, reachable: true},
+		{req: &pb.DistRequest{Id: 1, SrcLabel: "c", DestLabel: "a"}, expected: 0, reachable: false},
+	}
+
+	for _, tt := range tests {
+		res, err := client.Dist(context.Background(), tt.req)
+		if err != nil {
+			t.Errorf("Dist(%v) got unexpected error: %v", tt.req, err)
+			continue
+		}
+
+		if res.Result != tt.expected || res.Reachable != tt.reachable {
+			t.Errorf("Dist(%v) = %v, expected: %d (reachable: %v)", tt.req, res, tt.expected, tt.reachable)
+		}
+	}
+
+	path, err := client.Path(context.Background(), &pb.DistRequest{Id: 0, Src: 0, Dest: 3})
+	if err != nil {
+		t.Fatalf("Path got unexpected error: %v", err)
+	}
+	if expected := []int64{0, 1, 2, 3}; !reflect.DeepEqual(path.Vertices, expected) {
+		t.Errorf("Path got vertices %v, expected: %v", path.Vertices, expected)
+	}
+
+	// The coordinates are kept when the graph is mutated, and the new edges are taken into account
+	if _, err = client.AddEdges(context.Background(), &pb.AddEdgesRequest{
+		Id:    0,
+		Edges: []*pb.Edge{{Src: 3, Dest: 4, Weight: proto.Int32(1)}},
+	}); err != nil {
+		t.Fatalf("AddEdges got unexpected error: %v", err)
+	}
+	if _, err = client.AddVertices(context.Background(), &pb.AddVerticesRequest{
+		Id:          0,
+		Count:       1,
+		Coordinates: []*pb.Point{{X: -1, Y: -1}},
+	}); err != nil {
+		t.Fatalf("AddVertices got unexpected error: %v", err)
+	}
+	if _, err = client.AddEdges(context.Background(), &pb.AddEdgesRequest{
+		Id:    0,
+		Edges: []*pb.Edge{{Src: 5, Dest: 0, Weight: proto.Int32(1)}},
+	}); err != nil {
+		t.Fatalf("AddEdges got unexpected error: %v", err)
+	}
+
+	res, err := client.Dist(context.Background(), &pb.DistRequest{Id: 0, Src: 5, Dest: 4})
+	if err != nil {
+		t.Fatalf("Dist got unexpected error: %v", err)
+	}
+	if res.Result != 8 || !res.Reachable {
+		t.Errorf("Dist from 5 to 4 = %v, expected: %d", res, 8)
+	}
+
+	if graph, _ := testServer.store.Get(0); graph.coordinates == nil || len(graph.coordinates.points) != 6 {
+		t.Errorf("The mutated graph has coordinates %v, expected 6 points", graph.coordinates)
+	}
+}
+
+// TestAStarDistance_MatchesDijkstra tests that A* search finds the same shortest distances as the exhaustive search,
+// on grid graphs whose coordinates match the layout of the grid as well as on random graphs whose coordinates are
+// arbitrary
+func TestAStarDistance_MatchesDijkstra(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		var totalVertices int64
+		var edges []*pb.Edge
+		var coordinates []*pb.Point
+		if i%2 == 0 {
+			width, height := 1+r.Int63n(20), 1+r.Int63n(20)
+			totalVertices = width * height
+			edges, coordinates = gridGraph(width, height, i%4 == 0, int64(i))
+		} else {
+			totalVertices = 1 + r.Int63n(100)
+			edges = randomEdges(totalVertices, r.Intn(300), int64(i))
+			for _, edge := range edges {
+				if r.Intn(2) == 0 {
+					edge.Weight = proto.Int32(r.Int31n(100))
+				}
+			}
+			for j := int64(0); j < totalVertices; j++ {
+				coordinates = append(coordinates, &pb.Point{X: r.NormFloat64() * 1e3, Y: r.NormFloat64() * 1e-3})
+			}
+		}
+
+		directed := r.Intn(2) == 0
+		heuristic := pb.Heuristic_HEURISTIC_EUCLIDEAN
+		if r.Intn(2) == 0 {
+			heuristic = pb.Heuristic_HEURISTIC_MANHATTAN
+		}
+
+		exhaustive := newGraph(totalVertices, edges, directed)
+		graph := newGraph(totalVertices, edges, directed)
+		if err := attachCoordinates(&graph, coordinates, heuristic); err != nil {
+			t.Fatalf("attachCoordinates got unexpected error: %v", err)
+		}
+
+		for j := 0; j < 20; j++ {
+			src, dest := r.Int63n(totalVertices), r.Int63n(totalVertices)
+
+			expected, expectedReachable, _, err := computeShortestDistance(context.Background(), exhaustive, src,
+				dest)
+			if err != nil {
+				t.Fatalf("computeShortestDistance got unexpected error: %v", err)
+			}
+
+			actual, reachable, err := computeDistance(context.Background(), graph, src, dest)
+			if err != nil {
+				t.Fatalf("computeDistance got unexpected error: %v", err)
+			}
+
+			if actual != expected || reachable != expectedReachable {
+				t.Fatalf("Graph #%d: A* search got %d (reachable: %v) from %d to %d, expected: %d (reachable: %v)", i,
+					actual, reachable, src, dest, expected, expectedReachable)
+			}
+		}
+	}
+}
+
+// TestServer_AStarInvalidInput tests for invalid coordinates
+func TestServer_AStarInvalidInput(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	located, err := client.Post(context.Background(), &pb.PostRequest{
+		Edges:       []*pb.Edge{{SrcLabel: "a", DestLabel: "b"}},
+		Coordinates: []*pb.Point{{X: 0, Y: 0}, {X: 1, Y: 0}},
+	})
+	if err != nil {
+		t.Fatalf("Post got unexpected error: %v", err)
+	}
+
+	unlocated, err := client.Post(context.Background(), &pb.PostRequest{
+		TotalVertices: 2,
+		Edges:         []*pb.Edge{{Src: 0, Dest: 1}},
+	})
+	if err != nil {
+		t.Fatalf("Post got unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		req  proto.Message
+	}{
+		{
+			name: "fewer coordinates than vertices",
+			req:  &pb.PostRequest{TotalVertices: 3, Coordinates: []*pb.Point{{X: 0, Y: 0}, {X: 1, Y: 1}}},
+		},
+		{
+			name: "more coordinates than vertices",
+			req:  &pb.PostRequest{TotalVertices: 1, Coordinates: []*pb.Point{{X: 0, Y: 0}, {X: 1, Y: 1}}},
+		},
+		{
+			name: "coordinate not a number",
+			req:  &pb.PostRequest{TotalVertices: 1, Coordinates: []*pb.Point{{X: math.NaN(), Y: 0}}},
+		},
+		{
+			name: "infinite coordinate",
+			req:  &pb.PostRequest{TotalVertices: 1, Coordinates: []*pb.Point{{X: 0, Y: math.Inf(-1)}}},
+		},
+		{
+			name: "heuristic without coordinates",
+			req:  &pb.PostRequest{TotalVertices: 1, Heuristic: pb.Heuristic_HEURISTIC_MANHATTAN},
+		},
+		{
+			name: "unknown heuristic",
+			req:  &pb.PostRequest{TotalVertices: 1, Coordinates: []*pb.Point{{X: 0, Y: 0}}, Heuristic: 3},
+		},
+		{
+			name: "vertices added without coordinates",
+			req:  &pb.AddVerticesRequest{Id: located.Result, Labels: []string{"c"}},
+		},
+		{
+			name: "vertices added with coordinates to a graph without coordinates",
+			req:  &pb.AddVerticesRequest{Id: unlocated.Result, Count: 1, Coordinates: []*pb.Point{{X: 0, Y: 0}}},
+		},
+		{
+			name: "vertices added by their edges",
+			req:  &pb.AddEdgesRequest{Id: located.Result, Edges: []*pb.Edge{{SrcLabel: "a", DestLabel: "c"}}},
+		},
+	}
+
+	for _, tt := range tests {
+		var err error
+		switch req := tt.req.(type) {
+		case *pb.PostRequest:
+			_, err = client.Post(context.Background(), req)
+		case *pb.AddVerticesRequest:
+			_, err = client.AddVertices(context.Background(), req)
+		case *pb.AddEdgesRequest:
+			_, err = client.AddEdges(context.Background(), req)
+		}
+
+		if err == nil {
+			t.Fatal("Failed to catch expected error\n")
+		}
+
+		if code := status.Code(err); code != codes.InvalidArgument {
+			t.Errorf("%s: got error code %v, expected: %v", tt.name, code, codes.InvalidArgument)
+		}
+	}
+}
+
+// TestGraphFile_Coordinates tests that the vertex coordinates are kept by the binary format of the graphs
+func TestGraphFile_Coordinates(t *testing.T) {
+	edges, coordinates := gridGraph(3, 2, true, 1)
+	graph := newGraph(6, edges, false)
+	if err := attachCoordinates(&graph, coordinates, pb.Heuristic_HEURISTIC_MANHATTAN); err != nil {
+		t.Fatalf("attachCoordinates got unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err := encodeGraphFile(&buf, graph); err != nil {
+		t.Fatalf("encodeGraphFile got unexpected error: %v", err)
+	}
+
+	decoded, err := decodeGraphFile(&buf)
+	if err != nil {
+		t.Fatalf("decodeGraphFile got unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded.coordinates, graph.coordinates) {
+		t.Errorf("decodeGraphFile got coordinates %+v, expected: %+v", decoded.coordinates, graph.coordinates)
+	}
+}
+
+// BenchmarkShortestDistance_AStar compares A* search against BFS on a large grid, between two vertices of the same
+// column
+func BenchmarkShortestDistance_AStar(b *testing.B) {
+	const width = 500
+	edges, coordinates := gridGraph(width, width, false, 1)
+	graph := newGraph(width*width, edges, false)
+	if err := attachCoordinates(&graph, coordinates, pb.Heuristic_HEURISTIC_MANHATTAN); err != nil {
+		b.Fatalf("attachCoordinates got unexpected error: %v", err)
+	}
+
+	src := int64(width*width/2 + width/2)
+	dest := src + 100*width
+
+	b.Run("astar", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			getAStarDistance(context.Background(), graph.totalVertices, src, dest, graph.adj,
+				graphEstimator(graph, dest))
+		}
+	})
+
+	b.Run("bfs", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			getShortestDistance(context.Background(), graph.totalVertices, src, dest, graph.adj)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"context"
+)
+
+// Sides of a bidirectional search, recording from which end each vertex has been discovered
+const (
+	unvisited int8 = iota
+	fromSource
+	fromDestination
+)
+
+// getBidirectionalDistance takes the total number of vertices, the source node, the destination node,
+// as well as the adjacency structure of an undirected unweighted graph, and returns the shortest distance between
+// those two nodes and whether the destination node is reachable.
+// The search is aborted with an error once the context is done.
+// The function runs a BFS from both ends at once, always expanding the smaller frontier by one whole level, until the
+// two searches meet in the middle. On a graph of a low diameter, this visits far fewer vertices than a BFS from the
+// source node alone, which explores most of the graph before reaching the destination node.
+// The edges are traversed backwards by the search from the destination node, so the graph must be undirected.
+func getBidirectionalDistance(ctx context.Context, totalVertices int64, src int64, dest int64,
+	adj adjacency) (int64, bool, error) {
+	if src == dest {
+		return 0, true, nil
+	}
+
+	// side[i] is the end from which the ith vertex is discovered, and dist[i] is its distance to that end
+	side := make([]int8, totalVertices)
+	dist := make([]int64, totalVertices)
+
+	side[src] = fromSource
+	side[dest] = fromDestination
+	srcFrontier := []int64{src}
+	destFrontier := []int64{dest}
+
+	polled := 0
+	for len(srcFrontier) != 0 && len(destFrontier) != 0 {
+		frontier, own, other := srcFrontier, fromSource, fromDestination
+		if len(destFrontier) < len(srcFrontier) {
+			frontier, own, other = destFrontier, fromDestination, fromSource
+		}
+
+		// The whole level is expanded even once the searches have met, since a shorter meeting may still be found
+		// through another vertex of the same level
+		var next []int64
+		shortestDistance := int64(-1)
+		for _, node := range frontier {
+			polled++
+			if polled%cancellationCheckInterval == 0 {
+				if err := checkCancellation(ctx); err != nil {
+					return 0, false, err
+				}
+			}
+
+			for _, neighbour := range adj.neighbours(node) {
+				switch side[neighbour] {
+				case unvisited:
+					side[neighbour] = own
+					dist[neighbour] = dist[node] + 1
+					next = append(next, neighbour)
+				case other:
+					if meeting := dist[node] + 1 + dist[neighbour]; shortestDistance < 0 || meeting < shortestDistance {
+						shortestDistance = meeting
+					}
+				}
+			}
+		}
+
+		if shortestDistance >= 0 {
+			return shortestDistance, true, nil
+		}
+
+		if own == fromSource {
+			srcFrontier = next
+		} else {
+			destFrontier = next
+		}
+	}
+
+	return 0, false, nil
+}
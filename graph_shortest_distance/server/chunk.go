@@ -0,0 +1,38 @@
+package main
+
+import (
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+// maxChunkBytes is the encoded size up to which the chunks of a streamed response are filled. The chunks are sized by
+// bytes rather than by number of items, since the labels make the size of an item unbounded, so that every response
+// stays far below the default gRPC message size limit of 4 MiB.
+const maxChunkBytes = 1 << 20
+
+// nextChunkEnd returns the end of the chunk of items starting at start, which holds the items up to maxChunkBytes of
+// encoded size. size returns the encoded size of the item at the given index. A chunk holds at least one item, even
+// if this item alone exceeds maxChunkBytes.
+func nextChunkEnd(start int, total int, size func(i int) int) int {
+	end, bytes := start, 0
+	for end < total {
+		bytes += size(end)
+		if bytes > maxChunkBytes && end > start {
+			break
+		}
+		end++
+	}
+	return end
+}
+
+// messageFieldSize returns the encoded size of a message as an element of a repeated field, whose field number is
+// assumed to be below 16 so that its tag takes a single byte
+func messageFieldSize(m proto.Message) int {
+	return 1 + protowire.SizeBytes(proto.Size(m))
+}
+
+// stringFieldSize returns the encoded size of a string as an element of a repeated field, the same as
+// messageFieldSize
+func stringFieldSize(s string) int {
+	return 1 + protowire.SizeBytes(len(s))
+}
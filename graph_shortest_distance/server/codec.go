@@ -0,0 +1,486 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"hash/crc32"
+	"io"
+	"math"
+	"time"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// graphFileMagic identifies a file holding a single graph
+const graphFileMagic = "GSDG"
+
+// graphFileVersion is the version of the binary format written by encodeGraphFile.
+// Version 2 added the creation time of the graph, version 3 added the vertex labels, version 4 widened the vertices
+// and IDs to 64 bits, version 5 added the vertex coordinates, version 6 added the landmarks, and the earlier versions
+// are still readable.
+const graphFileVersion uint16 = 6
+
+// maxEncodedLabelLength is the maximum length of a vertex label accepted when decoding, which protects the decoder
+// against huge allocations when a length is corrupted
+const maxEncodedLabelLength = 1 << 20
+
+// errChecksumMismatch is returned when the checksum of an encoded file does not match its content
+var errChecksumMismatch = errors.New("checksum mismatch, the file is corrupted")
+
+// binaryWriter writes little-endian values, keeping a CRC-32 checksum of everything written.
+// The first error is kept, and turns all the subsequent writes into no-ops.
+type binaryWriter struct {
+	w   *bufio.Writer
+	crc hash.Hash32
+	buf [8]byte
+	err error
+}
+
+func newBinaryWriter(w io.Writer) *binaryWriter {
+	crc := crc32.NewIEEE()
+	return &binaryWriter{w: bufio.NewWriter(io.MultiWriter(w, crc)), crc: crc}
+}
+
+func (bw *binaryWriter) write(b []byte) {
+	if bw.err == nil {
+		_, bw.err = bw.w.Write(b)
+	}
+}
+
+func (bw *binaryWriter) uint8(v uint8) {
+	bw.buf[0] = v
+	bw.write(bw.buf[:1])
+}
+
+func (bw *binaryWriter) uint16(v uint16) {
+	binary.LittleEndian.PutUint16(bw.buf[:2], v)
+	bw.write(bw.buf[:2])
+}
+
+func (bw *binaryWriter) uint32(v uint32) {
+	binary.LittleEndian.PutUint32(bw.buf[:4], v)
+	bw.write(bw.buf[:4])
+}
+
+func (bw *binaryWriter) int32(v int32) {
+	bw.uint32(uint32(v))
+}
+
+func (bw *binaryWriter) uint64(v uint64) {
+	binary.LittleEndian.PutUint64(bw.buf[:8], v)
+	bw.write(bw.buf[:8])
+}
+
+func (bw *binaryWriter) int64(v int64) {
+	bw.uint64(uint64(v))
+}
+
+func (bw *binaryWriter) float64(v float64) {
+	bw.uint64(math.Float64bits(v))
+}
+
+// string writes the length of the string followed by its bytes
+func (bw *binaryWriter) string(v string) {
+	bw.uint32(uint32(len(v)))
+	bw.write([]byte(v))
+}
+
+func (bw *binaryWriter) bool(v bool) {
+	if v {
+		bw.uint8(1)
+	} else {
+		bw.uint8(0)
+	}
+}
+
+// checksum flushes the buffered data and appends the checksum of everything written so far
+func (bw *binaryWriter) checksum() error {
+	if bw.err == nil {
+		bw.err = bw.w.Flush()
+	}
+	bw.uint32(bw.crc.Sum32())
+	if bw.err == nil {
+		bw.err = bw.w.Flush()
+	}
+	return bw.err
+}
+
+// binaryReader reads the values written by binaryWriter, verifying its checksum.
+// The first error is kept, and turns all the subsequent reads into no-ops returning zero values.
+type binaryReader struct {
+	r   *bufio.Reader
+	crc hash.Hash32
+	buf [8]byte
+	err error
+	// offset is the number of bytes successfully read so far
+	offset int64
+}
+
+func newBinaryReader(r io.Reader) *binaryReader {
+	return &binaryReader{r: bufio.NewReader(r), crc: crc32.NewIEEE()}
+}
+
+func (br *binaryReader) read(b []byte) {
+	if br.err != nil {
+		for i := range b {
+			b[i] = 0
+		}
+		return
+	}
+	if _, br.err = io.ReadFull(br.r, b); br.err == nil {
+		br.crc.Write(b)
+		br.offset += int64(len(b))
+	} else if br.err == io.EOF {
+		br.err = io.ErrUnexpectedEOF
+	}
+}
+
+func (br *binaryReader) uint8() uint8 {
+	br.read(br.buf[:1])
+	return br.buf[0]
+}
+
+func (br *binaryReader) uint16() uint16 {
+	br.read(br.buf[:2])
+	return binary.LittleEndian.Uint16(br.buf[:2])
+}
+
+func (br *binaryReader) uint32() uint32 {
+	br.read(br.buf[:4])
+	return binary.LittleEndian.Uint32(br.buf[:4])
+}
+
+func (br *binaryReader) int32() int32 {
+	return int32(br.uint32())
+}
+
+func (br *binaryReader) uint64() uint64 {
+	br.read(br.buf[:8])
+	return binary.LittleEndian.Uint64(br.buf[:8])
+}
+
+func (br *binaryReader) int64() int64 {
+	return int64(br.uint64())
+}
+
+func (br *binaryReader) float64() float64 {
+	return math.Float64frombits(br.uint64())
+}
+
+// id reads a graph ID or a vertex, which was written as an int32 before the given version of the format, and as an
+// int64 since then
+func (br *binaryReader) id(version uint16, since uint16) int64 {
+	if version < since {
+		return int64(br.int32())
+	}
+	return br.int64()
+}
+
+// string reads a string written by binaryWriter.string, which must not be longer than maxLength
+func (br *binaryReader) string(maxLength uint32) string {
+	length := br.uint32()
+	if br.err == nil && length > maxLength {
+		br.err = fmt.Errorf("invalid string length: %d", length)
+	}
+	if br.err != nil {
+		return ""
+	}
+
+	b := make([]byte, length)
+	br.read(b)
+	return string(b)
+}
+
+func (br *binaryReader) bool() bool {
+	return br.uint8() != 0
+}
+
+// atEOF returns whether everything has been read
+func (br *binaryReader) atEOF() bool {
+	if br.err != nil {
+		return false
+	}
+	_, err := br.r.Peek(1)
+	return err == io.EOF
+}
+
+// resetChecksum starts a new checksum, so that the data read from now on can be verified on its own
+func (br *binaryReader) resetChecksum() {
+	br.crc.Reset()
+}
+
+// checksum reads the checksum appended by binaryWriter.checksum, and verifies it against everything read so far
+func (br *binaryReader) checksum() error {
+	expected := br.crc.Sum32()
+	if actual := br.uint32(); br.err == nil && actual != expected {
+		br.err = errChecksumMismatch
+	}
+	return br.err
+}
+
+// header writes the magic string and the format version
+func (bw *binaryWriter) header(magic string, version uint16) {
+	bw.write([]byte(magic))
+	bw.uint16(version)
+}
+
+// header reads the magic string and the format version, and fails unless the magic string matches the expected one
+// and the version is within the supported range. The version read is returned.
+func (br *binaryReader) header(magic string, minVersion uint16, maxVersion uint16) uint16 {
+	actualMagic := make([]byte, len(magic))
+	br.read(actualMagic)
+	actualVersion := br.uint16()
+
+	if br.err != nil {
+		return 0
+	}
+	if string(actualMagic) != magic {
+		br.err = fmt.Errorf("unrecognized file format")
+	} else if actualVersion < minVersion || actualVersion > maxVersion {
+		br.err = fmt.Errorf("unsupported format version: %d", actualVersion)
+	}
+	return actualVersion
+}
+
+// writeGraph writes the creation time, vertices, edges, vertex labels, vertex coordinates and landmarks of the graph.
+// Only the landmark vertices are written, since their distances are computed again when the graph is read.
+func writeGraph(bw *binaryWriter, graph Graph) {
+	writeEncodedGraph(bw, newEncodedGraph(graph))
+}
+
+// writeEncodedGraph writes the fields of a graph the same way as writeGraph, so that a graph read by readGraph can be
+// written again without being built
+func writeEncodedGraph(bw *binaryWriter, e encodedGraph) {
+	if e.createdAt.IsZero() {
+		bw.int64(0)
+	} else {
+		bw.int64(e.createdAt.UnixNano())
+	}
+	bw.int64(e.totalVertices)
+	bw.bool(e.directed)
+	bw.uint64(uint64(len(e.edges)))
+	for _, edge := range e.edges {
+		bw.int64(edge.Src)
+		bw.int64(edge.Dest)
+		bw.bool(edge.Weight != nil)
+		if edge.Weight != nil {
+			bw.int32(*edge.Weight)
+		}
+	}
+	bw.bool(e.labels != nil)
+	if e.labels != nil {
+		for _, label := range e.labels.labels {
+			bw.string(label)
+		}
+	}
+	bw.bool(e.hasCoordinates)
+	if e.hasCoordinates {
+		bw.uint8(uint8(e.heuristic))
+		for _, p := range e.points {
+			bw.float64(p.x)
+			bw.float64(p.y)
+		}
+	}
+	bw.bool(e.preprocessed)
+	if e.preprocessed {
+		bw.uint32(uint32(len(e.landmarks)))
+		for _, vertex := range e.landmarks {
+			bw.int64(vertex)
+		}
+	}
+}
+
+// encodedGraph holds the fields of a graph read by readGraph, from which the graph is built once the checksum covering
+// them has been verified
+type encodedGraph struct {
+	createdAt      time.Time
+	totalVertices  int64
+	directed       bool
+	edges          []*pb.Edge
+	labels         *labelDictionary
+	hasCoordinates bool
+	heuristic      pb.Heuristic
+	points         []point
+	preprocessed   bool
+	landmarks      []int64
+}
+
+// newEncodedGraph returns the fields of the graph written by writeGraph
+func newEncodedGraph(graph Graph) encodedGraph {
+	e := encodedGraph{
+		createdAt:     graph.createdAt,
+		totalVertices: graph.totalVertices,
+		directed:      graph.directed,
+		edges:         graph.edges,
+		labels:        graph.labels,
+	}
+	if graph.coordinates != nil {
+		e.hasCoordinates = true
+		e.heuristic = graph.coordinates.heuristic
+		e.points = graph.coordinates.points
+	}
+	if graph.landmarks != nil {
+		e.preprocessed = true
+		e.landmarks = graph.landmarks.vertices
+	}
+	return e
+}
+
+// build builds the graph, along with its adjacency structure and the distances of its landmarks
+func (e encodedGraph) build() (Graph, error) {
+	graph := newGraph(e.totalVertices, e.edges, e.directed)
+	graph.createdAt = e.createdAt
+	graph.labels = e.labels
+	if e.hasCoordinates {
+		graph.coordinates = newVertexCoordinates(e.points, e.heuristic, e.edges)
+	}
+	if e.preprocessed {
+		landmarks, err := newLandmarkDistances(context.Background(), graph, e.landmarks)
+		if err != nil {
+			return Graph{}, err
+		}
+		graph.landmarks = landmarks
+	}
+	return graph, nil
+}
+
+// readGraph reads a graph written by writeGraph in the given version of the enclosing format. The graph files,
+// snapshots and write-ahead logs share the same version numbers for the graph encoding. Version 1 did not record the
+// creation time, which is then left unknown, the versions before 3 did not record the vertex labels, the versions
+// before 4 recorded the vertices and the number of edges on 32 bits, the versions before 5 did not record the vertex
+// coordinates, and the versions before 6 did not record the landmarks.
+// Only the raw fields are read, so that nothing proportional to the decoded total number of vertices is allocated
+// before the checksum is verified, which a corrupted length would otherwise turn into a crash.
+func readGraph(br *binaryReader, version uint16) encodedGraph {
+	var createdAt time.Time
+	if version >= 2 {
+		if nanos := br.int64(); nanos != 0 {
+			createdAt = time.Unix(0, nanos)
+		}
+	}
+	totalVertices := br.id(version, 4)
+	directed := br.bool()
+	var totalEdges uint64
+	if version >= 4 {
+		totalEdges = br.uint64()
+	} else {
+		totalEdges = uint64(br.uint32())
+	}
+	if br.err != nil {
+		return encodedGraph{}
+	}
+	// The adjacency structure holds totalVertices + 1 offsets. The maximum of the server is not enforced here, so
+	// that the graphs stored before it was lowered can still be loaded.
+	if totalVertices < 0 || totalVertices == math.MaxInt64 || totalEdges > math.MaxInt64 {
+		br.err = fmt.Errorf("invalid graph size: %d vertices, %d edges", totalVertices, totalEdges)
+		return encodedGraph{}
+	}
+
+	var edges []*pb.Edge
+	for i := uint64(0); i < totalEdges && br.err == nil; i++ {
+		edge := &pb.Edge{Src: br.id(version, 4), Dest: br.id(version, 4)}
+		if br.bool() {
+			weight := br.int32()
+			edge.Weight = &weight
+		}
+		if br.err == nil && (edge.Src < 0 || edge.Src >= totalVertices || edge.Dest < 0 ||
+			edge.Dest >= totalVertices) {
+			br.err = fmt.Errorf("invalid edge: %d -> %d", edge.Src, edge.Dest)
+		}
+		edges = append(edges, edge)
+	}
+	if br.err != nil {
+		return encodedGraph{}
+	}
+
+	var labels *labelDictionary
+	if version >= 3 && br.bool() {
+		labels = &labelDictionary{indexes: make(map[string]int64)}
+		for i := int64(0); i < totalVertices && br.err == nil; i++ {
+			label := br.string(maxEncodedLabelLength)
+			if _, ok := labels.indexes[label]; br.err == nil && (label == "" || ok) {
+				br.err = fmt.Errorf("invalid vertex label: %q", label)
+			}
+			labels.add(label)
+		}
+	}
+	if br.err != nil {
+		return encodedGraph{}
+	}
+
+	var heuristic pb.Heuristic
+	var points []point
+	hasCoordinates := version >= 5 && br.bool()
+	if hasCoordinates {
+		heuristic = pb.Heuristic(br.uint8())
+		if br.err == nil && heuristic != pb.Heuristic_HEURISTIC_EUCLIDEAN &&
+			heuristic != pb.Heuristic_HEURISTIC_MANHATTAN {
+			br.err = fmt.Errorf("invalid heuristic: %d", heuristic)
+		}
+		for i := int64(0); i < totalVertices && br.err == nil; i++ {
+			p := point{x: br.float64(), y: br.float64()}
+			if br.err == nil && (!isFinite(p.x) || !isFinite(p.y)) {
+				br.err = fmt.Errorf("invalid vertex coordinates: (%v, %v)", p.x, p.y)
+			}
+			points = append(points, p)
+		}
+	}
+	if br.err != nil {
+		return encodedGraph{}
+	}
+
+	var landmarks []int64
+	preprocessed := version >= 6 && br.bool()
+	if preprocessed {
+		count := br.uint32()
+		if br.err == nil && (count == 0 || count > maxLandmarks) {
+			br.err = fmt.Errorf("invalid number of landmarks: %d", count)
+		}
+		for i := uint32(0); i < count && br.err == nil; i++ {
+			vertex := br.int64()
+			if br.err == nil && (vertex < 0 || vertex >= totalVertices) {
+				br.err = fmt.Errorf("invalid landmark: %d", vertex)
+			}
+			landmarks = append(landmarks, vertex)
+		}
+	}
+	if br.err != nil {
+		return encodedGraph{}
+	}
+
+	return encodedGraph{
+		createdAt:      createdAt,
+		totalVertices:  totalVertices,
+		directed:       directed,
+		edges:          edges,
+		labels:         labels,
+		hasCoordinates: hasCoordinates,
+		heuristic:      heuristic,
+		points:         points,
+		preprocessed:   preprocessed,
+		landmarks:      landmarks,
+	}
+}
+
+// encodeGraphFile writes the graph in the versioned binary format of a graph file, followed by its checksum
+func encodeGraphFile(w io.Writer, graph Graph) error {
+	bw := newBinaryWriter(w)
+	bw.header(graphFileMagic, graphFileVersion)
+	writeGraph(bw, graph)
+	return bw.checksum()
+}
+
+// decodeGraphFile reads a graph written by encodeGraphFile, and fails if the file is corrupted
+func decodeGraphFile(r io.Reader) (Graph, error) {
+	br := newBinaryReader(r)
+	version := br.header(graphFileMagic, 1, graphFileVersion)
+	encoded := readGraph(br, version)
+	if err := br.checksum(); err != nil {
+		return Graph{}, err
+	}
+	return encoded.build()
+}
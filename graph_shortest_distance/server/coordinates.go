@@ -0,0 +1,187 @@
+package main
+
+import (
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"math"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// maxEstimate caps the estimates of the heuristic, so that adding them to a distance never overflows
+const maxEstimate = 1 << 62
+
+// point is the position of a vertex
+type point struct {
+	x float64
+	y float64
+}
+
+// vertexCoordinates keeps the coordinates of every vertex of a graph, from which A* search estimates the remaining
+// distance to the destination node
+type vertexCoordinates struct {
+	points    []point
+	heuristic pb.Heuristic
+	// scale is the largest factor by which the distance between the coordinates of the two ends of any edge can be
+	// multiplied without exceeding the weight of the edge. Scaling the heuristic by it keeps the heuristic consistent
+	// whatever the coordinates and weights are given, so that A* search always finds the exact shortest distances.
+	scale float64
+}
+
+// newVertexCoordinates creates the coordinates of the vertices of a graph having the given edges, from already
+// validated points
+func newVertexCoordinates(points []point, heuristic pb.Heuristic, edges []*pb.Edge) *vertexCoordinates {
+	c := &vertexCoordinates{points: points, heuristic: heuristic}
+	c.scale = c.maxScale(edges)
+	return c
+}
+
+// withEdges returns the coordinates of the same vertices in a graph having the given edges instead, or nil if the
+// graph has no coordinates
+func (c *vertexCoordinates) withEdges(edges []*pb.Edge) *vertexCoordinates {
+	if c == nil {
+		return nil
+	}
+	return newVertexCoordinates(c.points, c.heuristic, edges)
+}
+
+// maxScale returns the scale of the heuristic for the given edges, which is 0 if no edge connects two vertices at
+// different coordinates, in which case the heuristic is of no help
+func (c *vertexCoordinates) maxScale(edges []*pb.Edge) float64 {
+	scale := math.Inf(1)
+	for _, edge := range edges {
+		if length := c.distance(edge.Src, edge.Dest); length > 0 {
+			scale = math.Min(scale, float64(edgeWeight(edge))/length)
+		}
+	}
+
+	if math.IsInf(scale, 1) {
+		return 0
+	}
+	return scale
+}
+
+// distance returns the distance between the coordinates of two vertices
+func (c *vertexCoordinates) distance(u int64, v int64) float64 {
+	dx := math.Abs(c.points[u].x - c.points[v].x)
+	dy := math.Abs(c.points[u].y - c.points[v].y)
+
+	if c.heuristic == pb.Heuristic_HEURISTIC_MANHATTAN {
+		return dx + dy
+	}
+	return math.Hypot(dx, dy)
+}
+
+// estimate returns a lower bound of the shortest distance from the vertex to the destination node. The estimate is
+// slightly shrunk before being rounded down, so that the rounding errors of the floating-point computations never
+// make it overestimate.
+func (c *vertexCoordinates) estimate(node int64, dest int64) int64 {
+	estimate := math.Floor(c.scale * c.distance(node, dest) * (1 - 1e-9))
+	if estimate > maxEstimate {
+		return maxEstimate
+	}
+	return int64(estimate)
+}
+
+// parsePoints validates the coordinates given for the vertices of a graph, and converts them
+func parsePoints(coordinates []*pb.Point) ([]point, error) {
+	points := make([]point, len(coordinates))
+	for i, coordinate := range coordinates {
+		if !isFinite(coordinate.GetX()) || !isFinite(coordinate.GetY()) {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				fmt.Sprintf("Invalid coordinates of the vertex #%d: (%v, %v). Must be finite numbers.", i,
+					coordinate.GetX(), coordinate.GetY()),
+			)
+		}
+		points[i] = point{x: coordinate.GetX(), y: coordinate.GetY()}
+	}
+	return points, nil
+}
+
+// validateHeuristic checks the heuristic requested for a graph having coordinates, and returns it with its default
+// applied
+func validateHeuristic(heuristic pb.Heuristic) (pb.Heuristic, error) {
+	switch heuristic {
+	case pb.Heuristic_HEURISTIC_UNSPECIFIED:
+		return pb.Heuristic_HEURISTIC_EUCLIDEAN, nil
+	case pb.Heuristic_HEURISTIC_EUCLIDEAN, pb.Heuristic_HEURISTIC_MANHATTAN:
+		return heuristic, nil
+	default:
+		return 0, status.Errorf(
+			codes.InvalidArgument,
+			fmt.Sprintf("Unsupported heuristic: %v", heuristic),
+		)
+	}
+}
+
+// attachCoordinates validates the coordinates given when posting a graph, and attaches them to the graph.
+// The graph is left without coordinates if none are given.
+func attachCoordinates(graph *Graph, coordinates []*pb.Point, heuristic pb.Heuristic) error {
+	if len(coordinates) == 0 {
+		if heuristic != pb.Heuristic_HEURISTIC_UNSPECIFIED {
+			return status.Error(codes.InvalidArgument, "A heuristic is given, but the vertices have no coordinates")
+		}
+		return nil
+	}
+
+	if int64(len(coordinates)) != graph.totalVertices {
+		return status.Errorf(
+			codes.InvalidArgument,
+			fmt.Sprintf("The number of coordinates (%d) differs from the total number of vertices (%d)",
+				len(coordinates), graph.totalVertices),
+		)
+	}
+
+	heuristic, err := validateHeuristic(heuristic)
+	if err != nil {
+		return err
+	}
+
+	points, err := parsePoints(coordinates)
+	if err != nil {
+		return err
+	}
+
+	graph.coordinates = newVertexCoordinates(points, heuristic, graph.edges)
+	return nil
+}
+
+// addCoordinates validates the coordinates given for the vertices added to a graph, and returns the coordinates of
+// all the vertices of the grown graph having the given edges. The coordinates are required if, and only if, the graph
+// already has coordinates.
+func (c *vertexCoordinates) addCoordinates(added int64, coordinates []*pb.Point,
+	edges []*pb.Edge) (*vertexCoordinates, error) {
+	if c == nil {
+		if len(coordinates) > 0 {
+			return nil, status.Error(codes.InvalidArgument,
+				"Coordinates are given, but the vertices of the graph have no coordinates")
+		}
+		return nil, nil
+	}
+
+	if int64(len(coordinates)) != added {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			fmt.Sprintf("The number of coordinates (%d) differs from the number of vertices to add (%d)",
+				len(coordinates), added),
+		)
+	}
+
+	points, err := parsePoints(coordinates)
+	if err != nil {
+		return nil, err
+	}
+
+	// The points are copied, since the previous graph may still be read by the queries
+	all := make([]point, 0, len(c.points)+len(points))
+	all = append(all, c.points...)
+	all = append(all, points...)
+	return newVertexCoordinates(all, c.heuristic, edges), nil
+}
+
+// isFinite reports whether the value is neither NaN nor infinite
+func isFinite(v float64) bool {
+	return !math.IsNaN(v) && !math.IsInf(v, 0)
+}
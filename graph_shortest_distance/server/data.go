@@ -1,17 +1,68 @@
 package main
 
 import (
+	"strconv"
+	"time"
+
 	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
 )
 
-// idHead is used to keep track of the next ID to assign to the next new graph
-var idHead int32
-
-// graphStore serves as an in-memory data store to keep the ID -> graph key-value pairs
-var graphStore map[int32]Graph
-
 // Graph is the abstract structure for representing a graph
 type Graph struct {
-	totalVertices int32
+	totalVertices int64
 	edges         []*pb.Edge
+	// weighted is set when at least one edge has a non-unit weight, in which case Dijkstra's algorithm is used
+	// instead of BFS for computing the shortest distance
+	weighted bool
+	// directed is set when the edges can only be traversed from their source node to their destination node
+	directed bool
+	// adj is the adjacency structure shared by all the queries on the graph
+	adj adjacency
+	// createdAt is the time the graph was posted, which is zero if unknown
+	createdAt time.Time
+	// labels maps the vertex labels of a labeled graph to their indexes, and is nil if the graph is not labeled
+	labels *labelDictionary
+	// coordinates are the positions of the vertices used by A* search, and is nil if none were given
+	coordinates *vertexCoordinates
+	// landmarks are the distances to and from the landmarks picked by Preprocess, and is nil unless the graph is
+	// preprocessed. They are discarded whenever the graph is modified.
+	landmarks *landmarkDistances
+	// version counts the updates of the graph made by mutate since the graph was loaded, so that a computation made
+	// outside of the mutation lock can tell whether the graph has changed meanwhile
+	version int64
+}
+
+// newGraph creates a graph from already validated vertices and edges
+func newGraph(totalVertices int64, edges []*pb.Edge, directed bool) Graph {
+	weighted := false
+	for _, edge := range edges {
+		if edgeWeight(edge) != 1 {
+			weighted = true
+			break
+		}
+	}
+
+	return Graph{
+		totalVertices: totalVertices,
+		edges:         edges,
+		weighted:      weighted,
+		directed:      directed,
+		adj:           buildAdjacency(totalVertices, edges, directed, weighted),
+	}
+}
+
+// nodeName returns the label of the vertex in a labeled graph, or its index otherwise
+func (g Graph) nodeName(index int64) string {
+	if g.labels != nil {
+		return g.labels.labels[index]
+	}
+	return strconv.Itoa(int(index))
+}
+
+// edgeWeight returns the weight of the edge, which defaults to 1 when no weight is specified
+func edgeWeight(edge *pb.Edge) int32 {
+	if edge.Weight == nil {
+		return 1
+	}
+	return *edge.Weight
 }
@@ -2,6 +2,9 @@ package main
 
 import (
 	"context"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"log"
 
 	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
@@ -9,11 +12,18 @@ import (
 
 // Delete deletes the graph associated with the specified ID and return true if the ID exists in the data store,
 // otherwise return false
-func (*Server) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResponse, error) {
+func (s *Server) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResponse, error) {
 	log.Printf("Delete was invoked with: %v\n", req)
 
-	_, ok := graphStore[req.Id]
-	delete(graphStore, req.Id)
+	s.mutationMu.Lock()
+	ok, err := s.store.Delete(req.Id)
+	s.mutationMu.Unlock()
+	if err != nil {
+		return nil, status.Errorf(
+			codes.Internal,
+			fmt.Sprintf("Failed to delete the graph[id=%d]: %v", req.Id, err),
+		)
+	}
 
 	return &pb.DeleteResponse{Result: ok}, nil
 }
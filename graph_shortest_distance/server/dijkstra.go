@@ -0,0 +1,87 @@
+package main
+
+import (
+	"container/heap"
+	"context"
+	"math"
+)
+
+// getShortestWeightedDistance takes the total number of vertices, the source node, the destination node,
+// as well as the weighted adjacency structure, and returns the shortest distance between those two nodes, or
+// math.MaxInt64 if they are not connected, along with the parent pointers recorded during the search.
+// The search is aborted with an error once the context is done.
+// The function uses Dijkstra's algorithm, which requires all edge weights to be non-negative.
+// The time complexity of this algorithm is O((V+E)logV), where V represents the number of vertices in the graph,
+// and E represents the number of edges in the graph.
+func getShortestWeightedDistance(ctx context.Context, totalVertices int64, src int64, dest int64,
+	adj adjacency) (int64, []int64, error) {
+	// The dist list records the shortest distance found so far of each vertex to the source node
+	dist := make([]int64, totalVertices)
+	for i := 0; i < len(dist); i++ {
+		dist[i] = math.MaxInt64
+	}
+
+	// parent[i] is the vertex through which the ith vertex is reached with the shortest distance found so far
+	parent := make([]int64, totalVertices)
+
+	// settled[] stores whether the shortest distance of the ith vertex is final
+	settled := make([]bool, totalVertices)
+
+	dist[src] = 0
+	pq := &distQueue{{node: src, dist: 0}}
+
+	// Dijkstra's algorithm
+	for polled := 1; pq.Len() != 0; polled++ {
+		if polled%cancellationCheckInterval == 0 {
+			if err := checkCancellation(ctx); err != nil {
+				return 0, nil, err
+			}
+		}
+
+		item := heap.Pop(pq).(distItem)
+		if settled[item.node] {
+			continue
+		}
+		settled[item.node] = true
+
+		if item.node == dest {
+			break
+		}
+
+		weights := adj.neighbourWeights(item.node)
+		for i, neighbour := range adj.neighbours(item.node) {
+			newDist := item.dist + int64(weights[i])
+			if !settled[neighbour] && newDist < dist[neighbour] {
+				dist[neighbour] = newDist
+				parent[neighbour] = item.node
+				heap.Push(pq, distItem{node: neighbour, dist: newDist})
+			}
+		}
+	}
+
+	return dist[dest], parent, nil
+}
+
+// distItem is an element of the priority queue used by Dijkstra's algorithm
+type distItem struct {
+	node int64
+	dist int64
+}
+
+// distQueue is a min-heap of distItem ordered by the distance, implementing heap.Interface
+type distQueue []distItem
+
+func (q distQueue) Len() int           { return len(q) }
+func (q distQueue) Less(i, j int) bool { return q[i].dist < q[j].dist }
+func (q distQueue) Swap(i, j int)      { q[i], q[j] = q[j], q[i] }
+
+func (q *distQueue) Push(x any) {
+	*q = append(*q, x.(distItem))
+}
+
+func (q *distQueue) Pop() any {
+	old := *q
+	item := old[len(old)-1]
+	*q = old[:len(old)-1]
+	return item
+}
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// maxPreallocatedEdges caps the number of edges allocated up front from the problem line of a DIMACS file, so that
+// a bogus problem line cannot exhaust the memory before any arc is read
+const maxPreallocatedEdges = 1 << 20
+
+// readDIMACS parses a graph in the shortest path format of the 9th DIMACS Implementation Challenge, and returns its
+// total number of vertices and its edges. The DIMACS vertices are numbered from 1 to n, and are mapped onto the
+// vertices 0 to n - 1 of the returned edges. A problem line declaring more than maxVertices vertices is rejected.
+func readDIMACS(r io.Reader, maxVertices int64) (int64, []*pb.Edge, error) {
+	scanner := bufio.NewScanner(r)
+
+	totalVertices := int64(-1)
+	var totalArcs int64
+	var edges []*pb.Edge
+
+	for line := 1; scanner.Scan(); line++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 {
+			continue
+		}
+
+		switch fields[0] {
+		case "c":
+			// Comment line
+		case "p":
+			if totalVertices >= 0 {
+				return 0, nil, fmt.Errorf("line %d: duplicated problem line", line)
+			}
+			if len(fields) != 4 || fields[1] != "sp" {
+				return 0, nil, fmt.Errorf("line %d: expected a problem line as \"p sp <n> <m>\"", line)
+			}
+
+			n, err := strconv.ParseInt(fields[2], 10, 64)
+			if err != nil || n < 0 || n > maxVertices {
+				return 0, nil, fmt.Errorf("line %d: invalid number of vertices: %s", line, fields[2])
+			}
+			m, err := strconv.ParseInt(fields[3], 10, 64)
+			if err != nil || m < 0 {
+				return 0, nil, fmt.Errorf("line %d: invalid number of arcs: %s", line, fields[3])
+			}
+
+			totalVertices = n
+			totalArcs = m
+			if m <= maxPreallocatedEdges {
+				edges = make([]*pb.Edge, 0, m)
+			} else {
+				edges = make([]*pb.Edge, 0, maxPreallocatedEdges)
+			}
+		case "a":
+			if totalVertices < 0 {
+				return 0, nil, fmt.Errorf("line %d: arc given before the problem line", line)
+			}
+			if len(fields) != 4 {
+				return 0, nil, fmt.Errorf("line %d: expected an arc line as \"a <u> <v> <w>\"", line)
+			}
+
+			var values [3]int64
+			for i := range values {
+				v, err := strconv.ParseInt(fields[i+1], 10, 64)
+				if err != nil {
+					return 0, nil, fmt.Errorf("line %d: invalid value: %s", line, fields[i+1])
+				}
+				values[i] = v
+			}
+
+			u, v, w := values[0], values[1], values[2]
+			if u < 1 || u > totalVertices || v < 1 || v > totalVertices {
+				return 0, nil, fmt.Errorf("line %d: the arc %d -> %d has a vertex outside of 1 to %d", line, u, v,
+					totalVertices)
+			}
+			if w < 0 || w > math.MaxInt32 {
+				return 0, nil, fmt.Errorf("line %d: invalid arc weight: %d", line, w)
+			}
+
+			weight := int32(w)
+			edges = append(edges, &pb.Edge{Src: u - 1, Dest: v - 1, Weight: &weight})
+		default:
+			return 0, nil, fmt.Errorf("line %d: unknown line type: %s", line, fields[0])
+		}
+	}
+
+	if err := scanner.Err(); err != nil {
+		return 0, nil, err
+	}
+
+	if totalVertices < 0 {
+		return 0, nil, fmt.Errorf("the problem line is missing")
+	}
+	if int64(len(edges)) != totalArcs {
+		return 0, nil, fmt.Errorf("the problem line announces %d arcs, but %d arcs are given", totalArcs, len(edges))
+	}
+
+	return totalVertices, edges, nil
+}
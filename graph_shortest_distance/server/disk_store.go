@@ -0,0 +1,208 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// graphFileExt is the file extension of the graph files kept in the data directory
+const graphFileExt = ".graph"
+
+// nextIdFile is the name of the file keeping the next ID to allocate in the data directory
+const nextIdFile = "next_id"
+
+// diskStore is an on-disk data store, keeping each graph in its own file under the data directory so that the graphs
+// survive a server restart. All the graphs are loaded into memory when the store is opened, and every mutation is
+// written through to the disk before it is applied in memory.
+type diskStore struct {
+	dir string
+	// writeMu serializes the writes to the graph files, which are done without holding mu so that the queries keep
+	// reading the graphs while a large graph is written to the disk
+	writeMu sync.Mutex
+	// mu guards the graphs map only
+	mu     sync.RWMutex
+	graphs map[int64]Graph
+	idMu   sync.Mutex
+	// idHead is used to keep track of the next ID to assign to the next new graph, and is persisted on every
+	// allocation so that IDs are never reused across restarts
+	idHead int64
+}
+
+// openDiskStore opens the data store kept under the given directory, creating the directory if needed, and loads
+// all the graphs previously saved in it
+func openDiskStore(dir string) (*diskStore, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	s := &diskStore{dir: dir, graphs: make(map[int64]Graph)}
+
+	content, err := os.ReadFile(filepath.Join(dir, nextIdFile))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, err
+	}
+	if err == nil {
+		idHead, err := strconv.ParseInt(strings.TrimSpace(string(content)), 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s file: %v", nextIdFile, err)
+		}
+		s.idHead = idHead
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasSuffix(name, graphFileExt) {
+			continue
+		}
+
+		id, err := strconv.ParseInt(strings.TrimSuffix(name, graphFileExt), 10, 64)
+		if err != nil {
+			continue
+		}
+
+		graph, err := s.readGraphFile(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load %s: %v", name, err)
+		}
+		s.graphs[id] = graph
+
+		// Never allocate an ID which is already taken, even if the next_id file is lost
+		if id >= s.idHead {
+			s.idHead = id + 1
+		}
+	}
+
+	return s, nil
+}
+
+func (s *diskStore) NextID() (int64, error) {
+	s.idMu.Lock()
+	defer s.idMu.Unlock()
+
+	id := s.idHead
+	err := writeFileAtomic(filepath.Join(s.dir, nextIdFile), func(w io.Writer) error {
+		_, err := fmt.Fprintf(w, "%d\n", id+1)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	s.idHead++
+	return id, nil
+}
+
+func (s *diskStore) Put(id int64, graph Graph) error {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	err := writeFileAtomic(s.graphPath(id), func(w io.Writer) error {
+		return encodeGraphFile(w, graph)
+	})
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	s.graphs[id] = graph
+	s.mu.Unlock()
+	return nil
+}
+
+func (s *diskStore) Get(id int64) (Graph, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	graph, ok := s.graphs[id]
+	return graph, ok
+}
+
+func (s *diskStore) Delete(id int64) (bool, error) {
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+
+	if _, ok := s.Get(id); !ok {
+		return false, nil
+	}
+	if err := os.Remove(s.graphPath(id)); err != nil && !os.IsNotExist(err) {
+		return false, err
+	}
+
+	s.mu.Lock()
+	delete(s.graphs, id)
+	s.mu.Unlock()
+	return true, nil
+}
+
+func (s *diskStore) List() []int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return sortedIds(s.graphs)
+}
+
+// graphPath returns the path of the file keeping the graph associated with the ID
+func (s *diskStore) graphPath(id int64) string {
+	return filepath.Join(s.dir, strconv.FormatInt(id, 10)+graphFileExt)
+}
+
+// readGraphFile reads and decodes the graph file with the given name in the data directory
+func (s *diskStore) readGraphFile(name string) (Graph, error) {
+	file, err := os.Open(filepath.Join(s.dir, name))
+	if err != nil {
+		return Graph{}, err
+	}
+	defer file.Close()
+
+	return decodeGraphFile(file)
+}
+
+// writeFileAtomic writes a file by writing a temporary file in the same directory first, syncing it, and then
+// renaming it, so that a crash never leaves a partially written file behind. The directory is synced after the
+// rename, so that the new file is durable once writeFileAtomic returns, and the files written one after the other
+// reach the disk in the same order.
+func writeFileAtomic(path string, write func(w io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err = write(tmp); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err = tmp.Close(); err != nil {
+		return err
+	}
+
+	if err = os.Rename(tmp.Name(), path); err != nil {
+		return err
+	}
+
+	return syncDir(filepath.Dir(path))
+}
+
+// syncDir syncs the directory, so that the files created, renamed or removed in it survive a crash
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+
+	return d.Sync()
+}
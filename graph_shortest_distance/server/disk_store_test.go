@@ -0,0 +1,177 @@
+package main
+
+import (
+	"bytes"
+	"encoding/binary"
+	"google.golang.org/protobuf/proto"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// TestDiskStore_Reopen tests that the graphs and the ID counter survive reopening the data store
+func TestDiskStore_Reopen(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := openDiskStore(dir)
+	if err != nil {
+		t.Fatalf("openDiskStore(%s) got unexpected error: %v", dir, err)
+	}
+
+	graphs := []Graph{
+		newGraph(4, []*pb.Edge{
+			{Src: 0, Dest: 1},
+			{Src: 1, Dest: 2},
+			{Src: 3, Dest: 0},
+		}, false),
+		newGraph(3, []*pb.Edge{
+			{Src: 0, Dest: 1, Weight: proto.Int32(5)},
+			{Src: 2, Dest: 1, Weight: proto.Int32(0)},
+		}, true),
+		newGraph(0, nil, false),
+	}
+
+	for i, graph := range graphs {
+		id, err := store.NextID()
+		if err != nil {
+			t.Fatalf("NextID() got unexpected error: %v", err)
+		}
+		if id != int64(i) {
+			t.Errorf("NextID() = %d, expected: %d", id, i)
+		}
+		if err = store.Put(id, graph); err != nil {
+			t.Fatalf("Put(%d) got unexpected error: %v", id, err)
+		}
+	}
+
+	if ok, err := store.Delete(2); err != nil || !ok {
+		t.Fatalf("Delete(2) = %v, %v, expected: true, nil", ok, err)
+	}
+
+	store, err = openDiskStore(dir)
+	if err != nil {
+		t.Fatalf("openDiskStore(%s) got unexpected error: %v", dir, err)
+	}
+
+	if ids := store.List(); !reflect.DeepEqual(ids, []int64{0, 1}) {
+		t.Errorf("List() = %v, expected: %v", ids, []int64{0, 1})
+	}
+
+	for i, expected := range graphs[:2] {
+		graph, ok := store.Get(int64(i))
+		if !ok {
+			t.Fatalf("Get(%d) did not find the graph", i)
+		}
+		if graph.totalVertices != expected.totalVertices || graph.directed != expected.directed ||
+			graph.weighted != expected.weighted || len(graph.edges) != len(expected.edges) {
+			t.Fatalf("Get(%d) = %+v, expected: %+v", i, graph, expected)
+		}
+		for j := range expected.edges {
+			if !proto.Equal(graph.edges[j], expected.edges[j]) {
+				t.Errorf("Get(%d) edge %d = %v, expected: %v", i, j, graph.edges[j], expected.edges[j])
+			}
+		}
+	}
+
+	// The ID of the deleted graph must not be reused
+	if id, err := store.NextID(); err != nil || id != 3 {
+		t.Errorf("NextID() = %d, %v, expected: 3, nil", id, err)
+	}
+}
+
+// TestDiskStore_GetDuringWrite tests that the graphs can be read while a graph file is being written
+func TestDiskStore_GetDuringWrite(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := openDiskStore(dir)
+	if err != nil {
+		t.Fatalf("openDiskStore(%s) got unexpected error: %v", dir, err)
+	}
+	if err = store.Put(0, newGraph(2, []*pb.Edge{{Src: 0, Dest: 1}}, false)); err != nil {
+		t.Fatalf("Put(0) got unexpected error: %v", err)
+	}
+
+	// The write lock is held for as long as a graph file is written, synced and renamed
+	store.writeMu.Lock()
+	defer store.writeMu.Unlock()
+
+	done := make(chan bool)
+	go func() {
+		_, ok := store.Get(0)
+		done <- ok && len(store.List()) == 1
+	}()
+
+	select {
+	case ok := <-done:
+		if !ok {
+			t.Errorf("Get(0) did not find the graph")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Get(0) is blocked by the graph file being written")
+	}
+}
+
+// TestDiskStore_Corrupted tests that a corrupted graph file is detected when opening the data store
+func TestDiskStore_Corrupted(t *testing.T) {
+	dir := t.TempDir()
+
+	store, err := openDiskStore(dir)
+	if err != nil {
+		t.Fatalf("openDiskStore(%s) got unexpected error: %v", dir, err)
+	}
+
+	err = store.Put(0, newGraph(3, []*pb.Edge{{Src: 0, Dest: 1}, {Src: 1, Dest: 2}}, false))
+	if err != nil {
+		t.Fatalf("Put(0) got unexpected error: %v", err)
+	}
+
+	path := filepath.Join(dir, "0"+graphFileExt)
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+
+	// Flip the bits of the destination node of the last edge
+	content[len(content)-6] ^= 0xff
+	if err = os.WriteFile(path, content, 0o644); err != nil {
+		t.Fatalf("Failed to write %s: %v", path, err)
+	}
+
+	if _, err = openDiskStore(dir); err == nil {
+		t.Fatal("Failed to catch expected error\n")
+	}
+}
+
+// TestGraphFile_CorruptedTotalVertices tests that a corrupted total number of vertices is caught by the checksum,
+// before anything proportional to it is allocated
+func TestGraphFile_CorruptedTotalVertices(t *testing.T) {
+	graph := newGraph(3, []*pb.Edge{{Src: 0, Dest: 1}, {Src: 1, Dest: 2}}, false)
+
+	var buf bytes.Buffer
+	if err := encodeGraphFile(&buf, graph); err != nil {
+		t.Fatalf("encodeGraphFile got unexpected error: %v", err)
+	}
+
+	// The total number of vertices follows the magic string, the version and the creation time
+	const offset = len(graphFileMagic) + 2 + 8
+	for _, totalVertices := range []uint64{1 << 40, math.MaxInt64, math.MaxUint64} {
+		content := append([]byte{}, buf.Bytes()...)
+		binary.LittleEndian.PutUint64(content[offset:], totalVertices)
+
+		if _, err := decodeGraphFile(bytes.NewReader(content)); err == nil {
+			t.Errorf("decodeGraphFile with %d vertices failed to catch expected error", totalVertices)
+		}
+	}
+
+	// A total number of vertices which is valid on its own is only caught by the checksum
+	content := append([]byte{}, buf.Bytes()...)
+	binary.LittleEndian.PutUint64(content[offset:], 1<<40)
+	if _, err := decodeGraphFile(bytes.NewReader(content)); err != errChecksumMismatch {
+		t.Errorf("decodeGraphFile got error %v, expected: %v", err, errChecksumMismatch)
+	}
+}
@@ -14,80 +14,151 @@ import (
 // Dist computes the shortest distance between the source node and destination node in the graph specified in the
 // request. If the specified source or destination node does not exist in the graph,
 // the server will send error accordingly.
-func (*Server) Dist(ctx context.Context, req *pb.DistRequest) (*pb.DistResponse, error) {
+// The shortest distance of a graph having vertex coordinates or preprocessed with landmarks is computed by A* search,
+// and the one of an undirected unweighted graph by a bidirectional BFS.
+func (s *Server) Dist(ctx context.Context, req *pb.DistRequest) (*pb.DistResponse, error) {
 	log.Printf("Dist was invoked with: %v\n", req)
 
-	graph, ok := graphStore[req.Id]
+	graph, err := s.queryGraph(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := queryNode(graph, req.Src, req.SrcLabel, "source")
+	if err != nil {
+		return nil, err
+	}
+
+	dest, err := queryNode(graph, req.Dest, req.DestLabel, "destination")
+	if err != nil {
+		return nil, err
+	}
+
+	shortestDistance, reachable, err := computeDistance(ctx, graph, src, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.DistResponse{Result: shortestDistance, Reachable: reachable}, nil
+}
+
+// queryGraph returns the graph associated with the ID given by a query, or a codes.NotFound error if it does not
+// exist in the data store
+func (s *Server) queryGraph(id int64) (Graph, error) {
+	graph, ok := s.store.Get(id)
 
 	// The graph does not exist in the data store
 	if !ok {
-		return nil, status.Errorf(
+		return Graph{}, status.Errorf(
 			codes.NotFound,
-			fmt.Sprintf("The graph[id=%d] does not exist in the data store", req.Id),
+			fmt.Sprintf("The graph[id=%d] does not exist in the data store", id),
 		)
 	}
 
-	totalVertices := graph.totalVertices
-	edges := graph.edges
+	return graph, nil
+}
 
-	// Parameter validation
-	if req.Src < 0 {
-		return nil, status.Errorf(
-			codes.InvalidArgument,
-			fmt.Sprintf("Invalid source node: %d. Must not be negative.", req.Src),
-		)
+// queryNode resolves a node given by a query, either by its index or by its label in a labeled graph, and checks that
+// it exists in the graph. The kind of the node, e.g. "source", is used in the error messages.
+func queryNode(graph Graph, index int64, label string, kind string) (int64, error) {
+	node, err := resolveNode(graph, index, label, kind)
+	if err != nil {
+		return 0, err
 	}
-	if req.Dest < 0 {
-		return nil, status.Errorf(
+
+	// Parameter validation
+	if node < 0 {
+		return 0, status.Errorf(
 			codes.InvalidArgument,
-			fmt.Sprintf("Invalid destination node: %d. Must not be negative.", req.Dest),
+			fmt.Sprintf("Invalid %s node: %d. Must not be negative.", kind, node),
 		)
 	}
-	if req.Src >= totalVertices {
-		return nil, status.Errorf(
+	if node >= graph.totalVertices {
+		return 0, status.Errorf(
 			codes.InvalidArgument,
-			fmt.Sprintf("The source node [%d] does not exist in the graph", req.Src),
+			fmt.Sprintf("The %s node [%d] does not exist in the graph", kind, node),
 		)
 	}
-	if req.Dest >= totalVertices {
-		return nil, status.Errorf(
-			codes.InvalidArgument,
-			fmt.Sprintf("The destination node [%d] does not exist in the graph", req.Dest),
-		)
+
+	return node, nil
+}
+
+// computeShortestDistance returns the shortest distance between the source node and the destination node of the
+// graph and whether the destination node is reachable at all, along with the parent pointers recorded during the
+// search, where parent[i] is the predecessor of the ith vertex on a shortest path from the source node.
+// The distance is 0 if the destination node is not reachable.
+// Graphs having vertex coordinates or preprocessed with landmarks are handled by A* search, graphs having non-unit edge
+// weights by Dijkstra's algorithm, while all the other graphs take the BFS fast path.
+// The search gives up with a codes.DeadlineExceeded or codes.Canceled error as soon as the context is done.
+func computeShortestDistance(ctx context.Context, graph Graph, src int64, dest int64) (int64, bool, []int64, error) {
+	if graph.coordinates != nil || graph.landmarks != nil || graph.weighted {
+		var shortestDistance int64
+		var parent []int64
+		var err error
+		if graph.coordinates != nil || graph.landmarks != nil {
+			shortestDistance, parent, err = getAStarDistance(ctx, graph.totalVertices, src, dest, graph.adj,
+				graphEstimator(graph, dest))
+		} else {
+			shortestDistance, parent, err = getShortestWeightedDistance(ctx, graph.totalVertices, src, dest, graph.adj)
+		}
+		if err != nil {
+			return 0, false, nil, err
+		}
+
+		if shortestDistance == math.MaxInt64 {
+			return 0, false, parent, nil
+		}
+		return shortestDistance, true, parent, nil
 	}
 
-	// Build adjacency list
-	adjList := make([][]int32, totalVertices)
-	for _, edge := range edges {
-		src := edge.Src
-		dest := edge.Dest
-		adjList[src] = append(adjList[src], dest)
-		adjList[dest] = append(adjList[dest], src)
+	return getShortestDistance(ctx, graph.totalVertices, src, dest, graph.adj)
+}
+
+// computeDistance returns the shortest distance between the source node and the destination node of the graph and
+// whether the destination node is reachable at all, for the queries which do not need the path itself.
+// Undirected unweighted graphs having neither vertex coordinates nor landmarks are searched from both ends at once,
+// while all the other graphs are searched the same way as computeShortestDistance.
+func computeDistance(ctx context.Context, graph Graph, src int64, dest int64) (int64, bool, error) {
+	if graph.coordinates == nil && graph.landmarks == nil && !graph.weighted && !graph.directed {
+		return getBidirectionalDistance(ctx, graph.totalVertices, src, dest, graph.adj)
 	}
 
-	shortestDistance := getShortestDistance(totalVertices, req.Src, req.Dest, adjList)
+	shortestDistance, reachable, _, err := computeShortestDistance(ctx, graph, src, dest)
+	return shortestDistance, reachable, err
+}
+
+// cancellationCheckInterval is the number of vertices a search visits between two checks of its context
+const cancellationCheckInterval = 1024
 
-	return &pb.DistResponse{Result: shortestDistance}, nil
+// checkCancellation returns the status error matching the context's error if the context is done, otherwise nil
+func checkCancellation(ctx context.Context) error {
+	if err := ctx.Err(); err != nil {
+		return status.FromContextError(err).Err()
+	}
+	return nil
 }
 
 // getShortestDistance takes the total number of vertices, the source node, the destination node,
-// as well as the adjacency list, and returns the shortest distance between those two nodes.
-// The function uses BFS algorithm, since the graph is undirected and unweighted.
+// as well as the adjacency structure, and returns the shortest distance between those two nodes and whether the
+// destination node is reachable, along with the parent pointers recorded during the search.
+// The search is aborted with an error once the context is done.
+// The function uses BFS algorithm, since the graph is unweighted.
 // The time complexity of this algorithm is O(V+E), where V represents the number of vertices in the graph,
 // and E represents the number of edges in the graph.
-func getShortestDistance(totalVertices int32, src int32, dest int32, adjList [][]int32) int32 {
+func getShortestDistance(ctx context.Context, totalVertices int64, src int64, dest int64,
+	adj adjacency) (int64, bool, []int64, error) {
+	// parent[i] is the vertex from which the ith vertex is discovered
+	parent := make([]int64, totalVertices)
+
 	if src == dest {
-		return 0
+		return 0, true, parent, nil
 	}
 
-	// The dist list records the shortest distance of each vertex to the source node
-	dist := make([]int32, totalVertices)
-	for i := 0; i < len(dist); i++ {
-		dist[i] = math.MaxInt32
-	}
+	// The dist list records the shortest distance of each visited vertex to the source node
+	dist := make([]int64, totalVertices)
 
 	// A queue to maintain queue of vertices whose adjacency list is to be scanned
-	var queue []int32
+	var queue []int64
 
 	// Boolean array visited[] which stores the information whether ith vertex is reached at least once in the
 	// breadth first search
@@ -98,16 +169,23 @@ func getShortestDistance(totalVertices int32, src int32, dest int32, adjList [][
 	queue = offer(queue, src)
 
 	// BFS algorithm
-	for len(queue) != 0 {
+	for polled := 1; len(queue) != 0; polled++ {
+		if polled%cancellationCheckInterval == 0 {
+			if err := checkCancellation(ctx); err != nil {
+				return 0, false, nil, err
+			}
+		}
+
 		nextNode := poll(&queue)
 		destFound := false
-		for i := 0; i < len(adjList[nextNode]); i++ {
-			if !visited[adjList[nextNode][i]] {
-				visited[adjList[nextNode][i]] = true
-				dist[adjList[nextNode][i]] = dist[nextNode] + 1
-				queue = offer(queue, adjList[nextNode][i])
-
-				if adjList[nextNode][i] == dest {
+		for _, neighbour := range adj.neighbours(nextNode) {
+			if !visited[neighbour] {
+				visited[neighbour] = true
+				dist[neighbour] = dist[nextNode] + 1
+				parent[neighbour] = nextNode
+				queue = offer(queue, neighbour)
+
+				if neighbour == dest {
 					destFound = true
 					break
 				}
@@ -118,17 +196,17 @@ func getShortestDistance(totalVertices int32, src int32, dest int32, adjList [][
 		}
 	}
 
-	return dist[dest]
+	return dist[dest], visited[dest], parent, nil
 }
 
 // offer takes the queue and enqueue the given element
-func offer(queue []int32, element int32) []int32 {
+func offer(queue []int64, element int64) []int64 {
 	queue = append(queue, element) // Offer element to the queue
 	return queue
 }
 
 // poll takes the queue and slice off the first element and return the element
-func poll(queue *[]int32) int32 {
+func poll(queue *[]int64) int64 {
 	element := (*queue)[0] // Poll element from the queue
 	*queue = (*queue)[1:]  // Slice off the element once it is dequeued.
 	return element
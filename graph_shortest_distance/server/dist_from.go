@@ -0,0 +1,171 @@
+package main
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"math"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// DistFrom computes the shortest distances from the source node to every vertex reachable from it in the graph
+// specified in the request, with a single traversal, and streams them back in chunks of (vertex, distance) pairs of at
+// most maxChunkBytes, in non-decreasing order of distance. When a maximum distance is given, the traversal stops past it, and the vertices
+// further away are not sent.
+func (s *Server) DistFrom(req *pb.DistFromRequest, stream pb.GraphService_DistFromServer) error {
+	log.Printf("DistFrom was invoked with: %v\n", req)
+
+	graph, err := s.queryGraph(req.Id)
+	if err != nil {
+		return err
+	}
+
+	src, err := queryNode(graph, req.Src, req.SrcLabel, "source")
+	if err != nil {
+		return err
+	}
+
+	// Parameter validation
+	maxDistance := int64(math.MaxInt64)
+	if req.MaxDistance != nil {
+		if *req.MaxDistance < 0 {
+			return status.Errorf(
+				codes.InvalidArgument,
+				fmt.Sprintf("Invalid maximum distance: %d. Must not be negative.", *req.MaxDistance),
+			)
+		}
+		maxDistance = *req.MaxDistance
+	}
+
+	order, dist, err := computeDistancesFrom(stream.Context(), graph, src, maxDistance)
+	if err != nil {
+		return err
+	}
+
+	distances := make([]*pb.VertexDistance, len(order))
+	for i, vertex := range order {
+		distances[i] = &pb.VertexDistance{Vertex: vertex, Distance: dist[vertex]}
+		if graph.labels != nil {
+			distances[i].Label = graph.labels.labels[vertex]
+		}
+	}
+
+	for start, end := 0, 0; start < len(distances); start = end {
+		end = nextChunkEnd(start, len(distances), func(i int) int { return messageFieldSize(distances[i]) })
+
+		if err := stream.Send(&pb.DistFromResponse{Distances: distances[start:end]}); err != nil {
+			log.Printf("Error while sending data to client: %v\n", err)
+			return status.Errorf(
+				status.Code(err),
+				fmt.Sprintf("Error while sending data to client: %v", err),
+			)
+		}
+	}
+
+	return nil
+}
+
+// computeDistancesFrom returns the vertices reachable from the source node within the maximum distance, in
+// non-decreasing order of distance, along with the shortest distances recorded during the search, where dist[i] is
+// the shortest distance of the ith vertex if it is one of the returned vertices.
+// Weighted graphs are handled by Dijkstra's algorithm, while all the other graphs take the BFS fast path, the same way
+// as computeShortestDistance.
+func computeDistancesFrom(ctx context.Context, graph Graph, src int64, maxDistance int64) ([]int64, []int64, error) {
+	if graph.weighted {
+		return getWeightedDistancesFrom(ctx, graph.totalVertices, src, maxDistance, graph.adj)
+	}
+
+	return getDistancesFrom(ctx, graph.totalVertices, src, maxDistance, graph.adj)
+}
+
+// getDistancesFrom runs a BFS from the source node over the whole unweighted graph, up to the maximum distance, and
+// returns the visited vertices in the order they are visited along with their distances to the source node.
+// The search is aborted with an error once the context is done.
+func getDistancesFrom(ctx context.Context, totalVertices int64, src int64, maxDistance int64,
+	adj adjacency) ([]int64, []int64, error) {
+	dist := make([]int64, totalVertices)
+	visited := make([]bool, totalVertices)
+
+	// The visited vertices in BFS order, which is also the queue of vertices whose adjacency list is to be scanned,
+	// starting at the head index
+	order := []int64{src}
+	visited[src] = true
+
+	for head := 0; head < len(order); head++ {
+		if (head+1)%cancellationCheckInterval == 0 {
+			if err := checkCancellation(ctx); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		nextNode := order[head]
+
+		// The neighbours would be further than the maximum distance
+		if dist[nextNode] >= maxDistance {
+			continue
+		}
+
+		for _, neighbour := range adj.neighbours(nextNode) {
+			if !visited[neighbour] {
+				visited[neighbour] = true
+				dist[neighbour] = dist[nextNode] + 1
+				order = append(order, neighbour)
+			}
+		}
+	}
+
+	return order, dist, nil
+}
+
+// getWeightedDistancesFrom runs Dijkstra's algorithm from the source node over the whole weighted graph, up to the
+// maximum distance, and returns the settled vertices in the order they are settled along with their distances to
+// the source node.
+// The search is aborted with an error once the context is done.
+func getWeightedDistancesFrom(ctx context.Context, totalVertices int64, src int64, maxDistance int64,
+	adj adjacency) ([]int64, []int64, error) {
+	dist := make([]int64, totalVertices)
+	for i := 0; i < len(dist); i++ {
+		dist[i] = math.MaxInt64
+	}
+
+	settled := make([]bool, totalVertices)
+
+	var order []int64
+	dist[src] = 0
+	pq := &distQueue{{node: src, dist: 0}}
+
+	for polled := 1; pq.Len() != 0; polled++ {
+		if polled%cancellationCheckInterval == 0 {
+			if err := checkCancellation(ctx); err != nil {
+				return nil, nil, err
+			}
+		}
+
+		item := heap.Pop(pq).(distItem)
+
+		// The vertices are popped in non-decreasing order of distance, so all the remaining ones are too far
+		if item.dist > maxDistance {
+			break
+		}
+		if settled[item.node] {
+			continue
+		}
+		settled[item.node] = true
+		order = append(order, item.node)
+
+		weights := adj.neighbourWeights(item.node)
+		for i, neighbour := range adj.neighbours(item.node) {
+			newDist := item.dist + int64(weights[i])
+			if !settled[neighbour] && newDist < dist[neighbour] {
+				dist[neighbour] = newDist
+				heap.Push(pq, distItem{node: neighbour, dist: newDist})
+			}
+		}
+	}
+
+	return order, dist, nil
+}
@@ -0,0 +1,298 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"reflect"
+	"testing"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// distFrom collects all the (vertex, distance) pairs streamed back by DistFrom
+func distFrom(client pb.GraphServiceClient, req *pb.DistFromRequest) ([]*pb.VertexDistance, error) {
+	stream, err := client.DistFrom(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var distances []*pb.VertexDistance
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return distances, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		distances = append(distances, res.Distances...)
+	}
+}
+
+// TestServer_DistFrom tests for computing the shortest distances from a single source node to all the other nodes
+func TestServer_DistFrom(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	graphs := []*pb.PostRequest{
+		{
+			TotalVertices: 6,
+			Edges: []*pb.Edge{
+				{Src: 0, Dest: 1},
+				{Src: 0, Dest: 2},
+				{Src: 1, Dest: 3},
+				{Src: 2, Dest: 3},
+				{Src: 3, Dest: 4},
+			},
+		},
+		{
+			TotalVertices: 4,
+			Edges: []*pb.Edge{
+				{Src: 0, Dest: 1, Weight: proto.Int32(4)},
+				{Src: 0, Dest: 2, Weight: proto.Int32(1)},
+				{Src: 2, Dest: 1, Weight: proto.Int32(1)},
+				{Src: 1, Dest: 3, Weight: proto.Int32(3)},
+			},
+			Directed: true,
+		},
+		{
+			Edges: []*pb.Edge{
+				{SrcLabel: "a", DestLabel: "b"},
+				{SrcLabel: "b", DestLabel: "c"},
+			},
+		},
+	}
+
+	for _, graph := range graphs {
+		if _, err := client.Post(context.Background(), graph); err != nil {
+			t.Fatalf("Post(%+v) got unexpected error: %v", graph, err)
+		}
+	}
+
+	tests := []struct {
+		req      *pb.DistFromRequest
+		expected map[int64]int64
+		labels   map[int64]string
+	}{
+		{
+			req:      &pb.DistFromRequest{Id: 0, Src: 0},
+			expected: map[int64]int64{0: 0, 1: 1, 2: 1, 3: 2, 4: 3},
+		},
+		{
+			req:      &pb.DistFromRequest{Id: 0, Src: 0, MaxDistance: proto.Int64(1)},
+			expected: map[int64]int64{0: 0, 1: 1, 2: 1},
+		},
+		{
+			req:      &pb.DistFromRequest{Id: 0, Src: 5},
+			expected: map[int64]int64{5: 0},
+		},
+		{
+			req:      &pb.DistFromRequest{Id: 1, Src: 0},
+			expected: map[int64]int64{0: 0, 2: 1, 1: 2, 3: 5},
+		},
+		{
+			req:      &pb.DistFromRequest{Id: 1, Src: 0, MaxDistance: proto.Int64(4)},
+			expected: map[int64]int64{0: 0, 2: 1, 1: 2},
+		},
+		{
+			// Only the vertices reachable along the directed edges are sent
+			req:      &pb.DistFromRequest{Id: 1, Src: 1},
+			expected: map[int64]int64{1: 0, 3: 3},
+		},
+		{
+			req:      &pb.DistFromRequest{Id: 2, SrcLabel: "c"},
+			expected: map[int64]int64{2: 0, 1: 1, 0: 2},
+			labels:   map[int64]string{0: "a", 1: "b", 2: "c"},
+		},
+	}
+
+	for _, tt := range tests {
+		distances, err := distFrom(client, tt.req)
+		if err != nil {
+			t.Errorf("DistFrom(%v) got unexpected error: %v", tt.req, err)
+			continue
+		}
+
+		got := make(map[int64]int64)
+		for i, vd := range distances {
+			if i > 0 && vd.Distance < distances[i-1].Distance {
+				t.Errorf("DistFrom(%v) = %v, expected non-decreasing distances", tt.req, distances)
+			}
+			if tt.labels != nil && vd.Label != tt.labels[vd.Vertex] {
+				t.Errorf("DistFrom(%v) got label %q for vertex %d, expected: %q", tt.req, vd.Label, vd.Vertex,
+					tt.labels[vd.Vertex])
+			}
+			got[vd.Vertex] = vd.Distance
+		}
+
+		if len(got) != len(distances) || !reflect.DeepEqual(got, tt.expected) {
+			t.Errorf("DistFrom(%v) = %v, expected: %v", tt.req, distances, tt.expected)
+		}
+	}
+}
+
+// TestServer_DistFromMatchesDist tests that the distances computed by DistFrom in a single traversal are the same as
+// the ones computed by Dist for every destination node, on large random graphs
+func TestServer_DistFromMatchesDist(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	const totalVertices = 66536
+	unweighted := randomEdges(totalVertices, 2*totalVertices, 1)
+	weighted := randomEdges(totalVertices, 2*totalVertices, 2)
+	for i, edge := range weighted {
+		edge.Weight = proto.Int32(int32(i % 10))
+	}
+
+	for id, edges := range [][]*pb.Edge{unweighted, weighted} {
+		graph := newGraph(totalVertices, edges, false)
+		testServer.store.Put(int64(id), graph)
+
+		distances, err := distFrom(client, &pb.DistFromRequest{Id: int64(id), Src: 0})
+		if err != nil {
+			t.Fatalf("DistFrom got unexpected error: %v", err)
+		}
+
+		got := make(map[int64]int64)
+		for _, vd := range distances {
+			got[vd.Vertex] = vd.Distance
+		}
+
+		// Sample the destination nodes, since every Dist call runs a traversal of its own
+		dests := make([]int64, 0, 50)
+		for dest := int64(0); dest < totalVertices; dest += totalVertices / 50 {
+			dests = append(dests, dest)
+		}
+
+		for _, dest := range dests {
+			expected, reachable, _, err := computeShortestDistance(context.Background(), graph, 0, dest)
+			if err != nil {
+				t.Fatalf("computeShortestDistance got unexpected error: %v", err)
+			}
+
+			distance, ok := got[dest]
+			if ok != reachable || distance != expected {
+				t.Errorf("Graph #%d: DistFrom got %d (reachable: %v) for vertex %d, expected: %d (reachable: %v)",
+					id, distance, ok, dest, expected, reachable)
+			}
+		}
+	}
+}
+
+// TestServer_DistFromLongLabels tests that the distances of a labeled graph are sent in chunks small enough for the
+// default gRPC message size limit, however long the labels are
+func TestServer_DistFromLongLabels(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	// A path of 100,000 vertices labeled by 64 bytes, whose distances would not fit in a single message
+	labels := make([]string, 100000)
+	edges := make([]*pb.Edge, len(labels)-1)
+	for i := range labels {
+		labels[i] = fmt.Sprintf("%064d", i)
+		if i > 0 {
+			edges[i-1] = &pb.Edge{Src: int64(i - 1), Dest: int64(i)}
+		}
+	}
+	dict, err := newLabelDictionary(labels)
+	if err != nil {
+		t.Fatalf("newLabelDictionary got unexpected error: %v", err)
+	}
+	graph := newGraph(int64(len(labels)), edges, false)
+	graph.labels = dict
+	testServer.store.Put(0, graph)
+
+	distances, err := distFrom(client, &pb.DistFromRequest{Id: 0, Src: 0})
+	if err != nil {
+		t.Fatalf("DistFrom got unexpected error: %v", err)
+	}
+
+	if len(distances) != len(labels) {
+		t.Fatalf("DistFrom returned %d distances, expected: %d", len(distances), len(labels))
+	}
+	for i, vd := range distances {
+		if vd.Vertex != int64(i) || vd.Distance != int64(i) || vd.Label != labels[i] {
+			t.Fatalf("DistFrom distance #%d = %v, expected: vertex %d at distance %d", i, vd, i, i)
+		}
+	}
+}
+
+// TestServer_DistFromInvalidInput tests for invalid parameters
+func TestServer_DistFromInvalidInput(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	_, err = client.Post(context.Background(), &pb.PostRequest{
+		TotalVertices: 3,
+		Edges: []*pb.Edge{
+			{Src: 0, Dest: 1},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Post got unexpected error")
+	}
+
+	reqs := []*pb.DistFromRequest{
+		// The queried graph does not exist
+		{Id: 1, Src: 0},
+		// Source node does not exist
+		{Id: 0, Src: 3},
+		// Src < 0
+		{Id: 0, Src: -1},
+		// Label on an unlabeled graph
+		{Id: 0, SrcLabel: "a"},
+		// Max distance < 0
+		{Id: 0, Src: 0, MaxDistance: proto.Int64(-1)},
+	}
+
+	for _, req := range reqs {
+		if _, err := distFrom(client, req); err == nil {
+			t.Fatal("Failed to catch expected error\n")
+		}
+	}
+}
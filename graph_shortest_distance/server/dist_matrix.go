@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+	"math"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// DistMatrix computes the shortest distance between every source node and every destination node given in the
+// request, and streams the matrix back one row per source node. Every row is computed by a single traversal from its
+// source node, which is shared by all the destination nodes.
+// The validation of the nodes is the same as the unary Dist service.
+// The rows are computed in parallel by a sendPool, the same way as DistStream, so the rows are sent as soon as they
+// are computed, which may not be the order of the request. The row index in every response is used to match them.
+func (s *Server) DistMatrix(req *pb.DistMatrixRequest, stream pb.GraphService_DistMatrixServer) error {
+	log.Printf("DistMatrix was invoked with: %v\n", req)
+
+	graph, err := s.queryGraph(req.Id)
+	if err != nil {
+		return err
+	}
+
+	srcs, err := queryNodes(graph, req.Srcs, req.SrcLabels, "source")
+	if err != nil {
+		return err
+	}
+
+	dests, err := queryNodes(graph, req.Dests, req.DestLabels, "destination")
+	if err != nil {
+		return err
+	}
+
+	// Parameter validation
+	if len(srcs) == 0 || len(dests) == 0 {
+		return status.Error(codes.InvalidArgument, "At least one source node and one destination node must be given")
+	}
+
+	// The remaining rows are given up as soon as one row fails
+	pool, ctx := newSendPool(stream.Context(), s.streamWorkers)
+
+	for row := 0; row < len(srcs) && ctx.Err() == nil; row++ {
+		row := row
+		pool.run(func() (func() error, error) {
+			res, err := distMatrixRow(ctx, graph, row, srcs[row], dests)
+			return func() error { return stream.Send(res) }, err
+		})
+	}
+
+	return pool.wait()
+}
+
+// queryNodes resolves the nodes given by a query, either by their indexes or by their labels in a labeled graph when
+// any label is given, and checks that they all exist in the graph
+func queryNodes(graph Graph, indexes []int64, labels []string, kind string) ([]int64, error) {
+	if len(labels) == 0 {
+		nodes := make([]int64, len(indexes))
+		for i, index := range indexes {
+			node, err := queryNode(graph, index, "", kind)
+			if err != nil {
+				return nil, err
+			}
+			nodes[i] = node
+		}
+		return nodes, nil
+	}
+
+	nodes := make([]int64, len(labels))
+	for i, label := range labels {
+		// An empty label would otherwise be taken as the index 0
+		if label == "" {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				fmt.Sprintf("The label of the %s node #%d must not be empty", kind, i),
+			)
+		}
+
+		node, err := queryNode(graph, 0, label, kind)
+		if err != nil {
+			return nil, err
+		}
+		nodes[i] = node
+	}
+	return nodes, nil
+}
+
+// distMatrixRow computes a single row of DistMatrix, with one traversal from the source node of the row
+func distMatrixRow(ctx context.Context, graph Graph, row int, src int64, dests []int64) (*pb.DistMatrixResponse,
+	error) {
+	order, dist, err := computeDistancesFrom(ctx, graph, src, math.MaxInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	// The distances are only recorded for the vertices reached by the traversal
+	reached := make([]bool, graph.totalVertices)
+	for _, vertex := range order {
+		reached[vertex] = true
+	}
+
+	res := &pb.DistMatrixResponse{
+		Row:       int64(row),
+		Src:       src,
+		Results:   make([]int64, len(dests)),
+		Reachable: make([]bool, len(dests)),
+	}
+	if graph.labels != nil {
+		res.SrcLabel = graph.labels.labels[src]
+	}
+
+	for i, dest := range dests {
+		if reached[dest] {
+			res.Results[i] = dist[dest]
+			res.Reachable[i] = true
+		}
+	}
+
+	return res, nil
+}
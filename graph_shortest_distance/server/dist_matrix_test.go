@@ -0,0 +1,308 @@
+package main
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"testing"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// distMatrix collects the rows streamed back by DistMatrix, in the order of the request
+func distMatrix(client pb.GraphServiceClient, req *pb.DistMatrixRequest) ([]*pb.DistMatrixResponse, error) {
+	stream, err := client.DistMatrix(context.Background(), req)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []*pb.DistMatrixResponse
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, res)
+	}
+
+	ordered := make([]*pb.DistMatrixResponse, len(rows))
+	for _, row := range rows {
+		if row.Row < 0 || row.Row >= int64(len(rows)) || ordered[row.Row] != nil {
+			return nil, status.Errorf(codes.Internal, "unexpected row index: %d", row.Row)
+		}
+		ordered[row.Row] = row
+	}
+	return ordered, nil
+}
+
+// TestServer_DistMatrix tests for computing the shortest distances between several source and destination nodes
+func TestServer_DistMatrix(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	graphs := []*pb.PostRequest{
+		{
+			TotalVertices: 5,
+			Edges: []*pb.Edge{
+				{Src: 0, Dest: 1, Weight: proto.Int32(4)},
+				{Src: 0, Dest: 2, Weight: proto.Int32(1)},
+				{Src: 2, Dest: 1, Weight: proto.Int32(1)},
+				{Src: 1, Dest: 3, Weight: proto.Int32(3)},
+			},
+			Directed: true,
+		},
+		{
+			Labels: []string{"spare"},
+			Edges: []*pb.Edge{
+				{SrcLabel: "a", DestLabel: "b"},
+				{SrcLabel: "b", DestLabel: "c"},
+			},
+		},
+	}
+
+	for _, graph := range graphs {
+		if _, err := client.Post(context.Background(), graph); err != nil {
+			t.Fatalf("Post(%+v) got unexpected error: %v", graph, err)
+		}
+	}
+
+	// -1 stands for a destination node which is not reachable
+	tests := []struct {
+		req      *pb.DistMatrixRequest
+		expected [][]int64
+	}{
+		{
+			req: &pb.DistMatrixRequest{Id: 0, Srcs: []int64{0, 1, 4, 0}, Dests: []int64{3, 1, 0}},
+			expected: [][]int64{
+				{5, 2, 0},
+				{3, 0, -1},
+				{-1, -1, -1},
+				{5, 2, 0},
+			},
+		},
+		{
+			req: &pb.DistMatrixRequest{
+				Id:         1,
+				SrcLabels:  []string{"c", "spare"},
+				DestLabels: []string{"a", "b", "spare"},
+			},
+			expected: [][]int64{
+				{2, 1, -1},
+				{-1, -1, 0},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		rows, err := distMatrix(client, tt.req)
+		if err != nil {
+			t.Errorf("DistMatrix(%v) got unexpected error: %v", tt.req, err)
+			continue
+		}
+
+		if len(rows) != len(tt.expected) {
+			t.Errorf("DistMatrix(%v) got %d rows, expected: %d", tt.req, len(rows), len(tt.expected))
+			continue
+		}
+
+		for i, row := range rows {
+			for j, expected := range tt.expected[i] {
+				reachable := expected >= 0
+				if expected < 0 {
+					expected = 0
+				}
+
+				if row.Results[j] != expected || row.Reachable[j] != reachable {
+					t.Errorf("DistMatrix(%v)[%d][%d] = %d (reachable: %v), expected: %d (reachable: %v)", tt.req, i,
+						j, row.Results[j], row.Reachable[j], expected, reachable)
+				}
+			}
+
+			if len(tt.req.SrcLabels) > 0 && row.SrcLabel != tt.req.SrcLabels[i] {
+				t.Errorf("DistMatrix(%v) row #%d has source label %q, expected: %q", tt.req, i, row.SrcLabel,
+					tt.req.SrcLabels[i])
+			}
+		}
+	}
+}
+
+// TestServer_DistMatrixMatchesDist tests that every cell of a matrix computed by DistMatrix is the same as the
+// shortest distance computed for the same pair of nodes by Dist
+func TestServer_DistMatrixMatchesDist(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	const totalVertices = 2000
+	unweighted := randomEdges(totalVertices, totalVertices, 1)
+	weighted := randomEdges(totalVertices, totalVertices, 2)
+	for i, edge := range weighted {
+		edge.Weight = proto.Int32(int32(i % 10))
+	}
+
+	for id, edges := range [][]*pb.Edge{unweighted, weighted} {
+		testServer.store.Put(int64(id), newGraph(totalVertices, edges, id == 1))
+
+		var srcs, dests []int64
+		for i := int64(0); i < 20; i++ {
+			srcs = append(srcs, i*97%totalVertices)
+			dests = append(dests, i*31%totalVertices)
+		}
+
+		rows, err := distMatrix(client, &pb.DistMatrixRequest{Id: int64(id), Srcs: srcs, Dests: dests})
+		if err != nil {
+			t.Fatalf("DistMatrix got unexpected error: %v", err)
+		}
+
+		for i, src := range srcs {
+			for j, dest := range dests {
+				res, err := client.Dist(context.Background(), &pb.DistRequest{Id: int64(id), Src: src, Dest: dest})
+				if err != nil {
+					t.Fatalf("Dist got unexpected error: %v", err)
+				}
+
+				if rows[i].Results[j] != res.Result || rows[i].Reachable[j] != res.Reachable {
+					t.Errorf("Graph #%d: DistMatrix got %d (reachable: %v) from %d to %d, expected: %v", id,
+						rows[i].Results[j], rows[i].Reachable[j], src, dest, res)
+				}
+			}
+		}
+	}
+}
+
+// TestServer_DistMatrixInvalidInput tests for invalid parameters
+func TestServer_DistMatrixInvalidInput(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	for _, graph := range []*pb.PostRequest{
+		{TotalVertices: 3, Edges: []*pb.Edge{{Src: 0, Dest: 1}}},
+		{Edges: []*pb.Edge{{SrcLabel: "a", DestLabel: "b"}}},
+	} {
+		if _, err := client.Post(context.Background(), graph); err != nil {
+			t.Fatalf("Post got unexpected error")
+		}
+	}
+
+	reqs := []*pb.DistMatrixRequest{
+		// The queried graph does not exist
+		{Id: 2, Srcs: []int64{0}, Dests: []int64{0}},
+		// Source node does not exist
+		{Id: 0, Srcs: []int64{0, 3}, Dests: []int64{0}},
+		// Destination node < 0
+		{Id: 0, Srcs: []int64{0}, Dests: []int64{1, -1}},
+		// No source node
+		{Id: 0, Dests: []int64{0}},
+		// No destination node
+		{Id: 0, Srcs: []int64{0}},
+		// Label on an unlabeled graph
+		{Id: 0, SrcLabels: []string{"a"}, Dests: []int64{0}},
+		// Unknown label
+		{Id: 1, SrcLabels: []string{"a"}, DestLabels: []string{"c"}},
+		// Empty label
+		{Id: 1, SrcLabels: []string{"a", ""}, DestLabels: []string{"b"}},
+	}
+
+	for _, req := range reqs {
+		if _, err := distMatrix(client, req); err == nil {
+			t.Fatal("Failed to catch expected error\n")
+		}
+	}
+}
+
+// BenchmarkServer_DistMatrix compares computing a distance table with DistMatrix, which runs one traversal per source
+// node, against sending one DistStream request per cell
+func BenchmarkServer_DistMatrix(b *testing.B) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		b.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	const totalVertices = 100_000
+	const size = 20
+	testServer.store.Put(0, newGraph(totalVertices, randomEdges(totalVertices, 400_000, 1), false))
+
+	var srcs, dests []int64
+	for i := int64(0); i < size; i++ {
+		srcs = append(srcs, i*4999%totalVertices)
+		dests = append(dests, i*7919%totalVertices)
+	}
+
+	b.Run("dist_matrix", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			if _, err := distMatrix(client, &pb.DistMatrixRequest{Id: 0, Srcs: srcs, Dests: dests}); err != nil {
+				b.Fatalf("DistMatrix got unexpected error: %v", err)
+			}
+		}
+	})
+
+	b.Run("dist_stream", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			stream, err := client.DistStream(context.Background())
+			if err != nil {
+				b.Fatalf("Error while opening stream: %v\n", err)
+			}
+
+			go func() {
+				for _, src := range srcs {
+					for _, dest := range dests {
+						stream.Send(&pb.DistRequest{Id: 0, Src: src, Dest: dest})
+					}
+				}
+				stream.CloseSend()
+			}()
+
+			for {
+				if _, err := stream.Recv(); err == io.EOF {
+					break
+				} else if err != nil {
+					b.Fatalf("Error while receiving response: %v\n", err)
+				}
+			}
+		}
+	})
+}
@@ -1,8 +1,8 @@
 package main
 
 import (
+	"context"
 	"fmt"
-	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"io"
 	"log"
@@ -12,80 +12,91 @@ import (
 
 // DistStream handles multiple requests for computing the shortest distance between two nodes received over a stream,
 // and then sends back responses accordingly over the same stream.
-// The computation algorithm and  behavior of each response are the same as the unary Dist service.
-func (*Server) DistStream(stream pb.GraphService_DistStreamServer) error {
+// The computation algorithm and  behavior of each response are the same as the unary Dist service, except that
+// a failed request does not end the stream. Its error is reported in the response instead, and the stream carries
+// on with the next request.
+// The requests are computed in parallel by a sendPool, a bounded pool of workers, so the responses are sent as soon as
+// they are computed, which may not be the order of the requests. The request ID echoed in every response is used to match them.
+func (s *Server) DistStream(stream pb.GraphService_DistStreamServer) error {
 	log.Println("DistStream was invoked")
 
+	// The failed requests are reported in their responses, so the pool only fails once sending fails, and the
+	// remaining responses are dropped, since the stream is broken
+	pool, ctx := newSendPool(stream.Context(), s.streamWorkers)
+
+	var recvErr error
 	for {
 		req, err := stream.Recv()
 
 		if err == io.EOF {
-			return nil
+			break
 		}
 
 		if err != nil {
-			log.Fatalf("Error while reading client stream: %v\n", err)
+			recvErr = err
+			break
 		}
 
-		graph, ok := graphStore[req.Id]
+		pool.run(func() (func() error, error) {
+			res := s.distStreamResponse(ctx, req)
+			return func() error { return stream.Send(res) }, nil
+		})
+	}
 
-		// The graph does not exist in the data store
-		if !ok {
-			return status.Errorf(
-				codes.NotFound,
-				fmt.Sprintf("The graph[id=%d] does not exist in the data store", req.Id),
-			)
-		}
+	sendErr := pool.wait()
 
-		totalVertices := graph.totalVertices
-		edges := graph.edges
+	// Only this stream is ended, e.g. when the client disconnects, while the server keeps serving other clients
+	if recvErr != nil {
+		log.Printf("Error while reading client stream: %v\n", recvErr)
+		return status.Errorf(
+			status.Code(recvErr),
+			fmt.Sprintf("Error while reading client stream: %v", recvErr),
+		)
+	}
 
-		// Parameter validation
-		if req.Src < 0 {
-			return status.Errorf(
-				codes.InvalidArgument,
-				fmt.Sprintf("Invalid source node: %d. Must not be negative.", req.Src),
-			)
-		}
-		if req.Dest < 0 {
-			return status.Errorf(
-				codes.InvalidArgument,
-				fmt.Sprintf("Invalid destination node: %d. Must not be negative.", req.Dest),
-			)
-		}
-		if req.Src >= totalVertices {
-			return status.Errorf(
-				codes.InvalidArgument,
-				fmt.Sprintf("The source node [%d] does not exist in the graph", req.Src),
-			)
-		}
-		if req.Dest >= totalVertices {
-			return status.Errorf(
-				codes.InvalidArgument,
-				fmt.Sprintf("The destination node [%d] does not exist in the graph", req.Dest),
-			)
-		}
+	return sendErr
+}
 
-		// Build adjacency list
-		adjList := make([][]int32, totalVertices)
-		for _, edge := range edges {
-			src := edge.Src
-			dest := edge.Dest
-			adjList[src] = append(adjList[src], dest)
-			adjList[dest] = append(adjList[dest], src)
-		}
+// distStreamResponse computes the response to a single request received by DistStream
+func (s *Server) distStreamResponse(ctx context.Context, req *pb.DistRequest) *pb.DistStreamResponse {
+	res := &pb.DistStreamResponse{
+		Id:        req.Id,
+		Src:       req.Src,
+		Dest:      req.Dest,
+		RequestId: req.RequestId,
+		SrcLabel:  req.SrcLabel,
+		DestLabel: req.DestLabel,
+	}
 
-		shortestDistance := getShortestDistance(totalVertices, req.Src, req.Dest, adjList)
+	shortestDistance, reachable, err := s.distStreamItem(ctx, req)
+	if err != nil {
+		sts := status.Convert(err)
+		res.ErrorCode = int32(sts.Code())
+		res.ErrorMessage = sts.Message()
+	} else {
+		res.Result = shortestDistance
+		res.Reachable = reachable
+	}
 
-		err = stream.Send(&pb.DistStreamResponse{
-			Result: shortestDistance,
-			Id:     req.Id,
-			Src:    req.Src,
-			Dest:   req.Dest,
-		})
+	return res
+}
 
-		if err != nil {
-			log.Fatalf("Error while sending data to client: %v\n", err)
-		}
+// distStreamItem computes the shortest distance for a single request received by DistStream
+func (s *Server) distStreamItem(ctx context.Context, req *pb.DistRequest) (int64, bool, error) {
+	graph, err := s.queryGraph(req.Id)
+	if err != nil {
+		return 0, false, err
 	}
+
+	src, err := queryNode(graph, req.Src, req.SrcLabel, "source")
+	if err != nil {
+		return 0, false, err
+	}
+
+	dest, err := queryNode(graph, req.Dest, req.DestLabel, "destination")
+	if err != nil {
+		return 0, false, err
+	}
+
+	return computeDistance(ctx, graph, src, dest)
 }
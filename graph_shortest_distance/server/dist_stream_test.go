@@ -8,16 +8,263 @@ import (
 	"google.golang.org/grpc/status"
 	"io"
 	"log"
-	"math"
 	"testing"
+	"time"
 
 	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
 )
 
+// TestServer_DistStream tests that failed requests are reported in their own responses without ending the stream
+func TestServer_DistStream(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	_, err = client.Post(context.Background(), &pb.PostRequest{
+		TotalVertices: 4,
+		Edges: []*pb.Edge{
+			{Src: 0, Dest: 1},
+			{Src: 1, Dest: 2},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Post got unexpected error")
+	}
+
+	tests := []struct {
+		expected    int64
+		unreachable bool
+		errorCode   codes.Code
+		id          int64
+		src         int64
+		dest        int64
+	}{
+		{expected: 2, errorCode: codes.OK, id: 0, src: 0, dest: 2},
+		{errorCode: codes.NotFound, id: 1, src: 0, dest: 2},
+		{expected: 1, errorCode: codes.OK, id: 0, src: 2, dest: 1},
+		{errorCode: codes.InvalidArgument, id: 0, src: 4, dest: 1},
+		{errorCode: codes.InvalidArgument, id: 0, src: 0, dest: -1},
+		{unreachable: true, errorCode: codes.OK, id: 0, src: 3, dest: 0},
+	}
+
+	stream, err := client.DistStream(context.Background())
+	if err != nil {
+		t.Fatalf("Error while opening stream: %v\n", err)
+	}
+
+	for i, tt := range tests {
+		err := stream.Send(&pb.DistRequest{
+			Id:        tt.id,
+			Src:       tt.src,
+			Dest:      tt.dest,
+			RequestId: int64(i),
+		})
+
+		if err != nil {
+			t.Fatalf("Error while sending request: %v\n", err)
+		}
+	}
+	stream.CloseSend()
+
+	received := 0
+	for {
+		res, err := stream.Recv()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Error while receiving response: %v\n", err)
+		}
+
+		tt := tests[res.RequestId]
+		received++
+
+		if codes.Code(res.ErrorCode) != tt.errorCode {
+			t.Errorf("DistStream(%+v) error code = %v, expected: %v", tt, codes.Code(res.ErrorCode), tt.errorCode)
+		}
+		if tt.errorCode != codes.OK && res.ErrorMessage == "" {
+			t.Errorf("DistStream(%+v) got empty error message", tt)
+		}
+		if res.Result != tt.expected || (tt.errorCode == codes.OK && res.Reachable == tt.unreachable) {
+			t.Errorf("DistStream(%+v) = %v, expected: %v", tt, res.Result, tt.expected)
+		}
+		if res.Id != tt.id || res.Src != tt.src || res.Dest != tt.dest {
+			t.Errorf("DistStream(%+v) echoed request = [id=%d src=%d dest=%d]", tt, res.Id, res.Src, res.Dest)
+		}
+	}
+
+	if received != len(tests) {
+		t.Errorf("DistStream received %d responses, expected: %d", received, len(tests))
+	}
+}
+
+// TestServer_DistStreamConcurrent tests that a slow request does not hold back the cheap requests sent after it
+func TestServer_DistStreamConcurrent(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	// A long path whose ends are far apart, saved in the data store directly since it does not fit in one message
+	const totalVertices = 2_000_000
+	edges := make([]*pb.Edge, totalVertices-1)
+	for i := range edges {
+		edges[i] = &pb.Edge{Src: int64(i), Dest: int64(i + 1)}
+	}
+	testServer.store.Put(0, newGraph(totalVertices, edges, false))
+
+	stream, err := client.DistStream(context.Background())
+	if err != nil {
+		t.Fatalf("Error while opening stream: %v\n", err)
+	}
+
+	const cheapRequests = 20
+	if err = stream.Send(&pb.DistRequest{Id: 0, Src: 0, Dest: totalVertices - 1, RequestId: 0}); err != nil {
+		t.Fatalf("Error while sending request: %v\n", err)
+	}
+	for i := 1; i <= cheapRequests; i++ {
+		err = stream.Send(&pb.DistRequest{Id: 0, Src: int64(i), Dest: int64(i + 1), RequestId: int64(i)})
+		if err != nil {
+			t.Fatalf("Error while sending request: %v\n", err)
+		}
+	}
+	stream.CloseSend()
+
+	var order []int64
+	for {
+		res, err := stream.Recv()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			t.Fatalf("Error while receiving response: %v\n", err)
+		}
+
+		expected := int64(1)
+		if res.RequestId == 0 {
+			expected = totalVertices - 1
+		}
+		if res.Result != expected {
+			t.Errorf("DistStream(request #%d) = %v, expected: %v", res.RequestId, res.Result, expected)
+		}
+
+		order = append(order, res.RequestId)
+	}
+
+	if len(order) != cheapRequests+1 {
+		t.Fatalf("DistStream received %d responses, expected: %d", len(order), cheapRequests+1)
+	}
+	if order[0] == 0 {
+		t.Errorf("The slow request was answered before the cheap requests: %v", order)
+	}
+}
+
+// TestServer_DistStreamClientCancel tests that a client cancelling its stream only ends that stream, while the server
+// keeps serving other requests
+func TestServer_DistStreamClientCancel(t *testing.T) {
+	testServer.store = newMemoryStore()
+	drainStreamErrors()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	_, err = client.Post(context.Background(), &pb.PostRequest{
+		TotalVertices: 3,
+		Edges: []*pb.Edge{
+			{Src: 0, Dest: 1},
+			{Src: 1, Dest: 2},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Post got unexpected error")
+	}
+
+	streamCtx, cancel := context.WithCancel(context.Background())
+	stream, err := client.DistStream(streamCtx)
+	if err != nil {
+		t.Fatalf("Error while opening stream: %v\n", err)
+	}
+
+	if err = stream.Send(&pb.DistRequest{Id: 0, Src: 0, Dest: 2}); err != nil {
+		t.Fatalf("Error while sending request: %v\n", err)
+	}
+	if _, err = stream.Recv(); err != nil {
+		t.Fatalf("Error while receiving response: %v\n", err)
+	}
+
+	// Disconnect in the middle of the stream
+	cancel()
+
+	select {
+	case err = <-streamErrors:
+		if status.Code(err) != codes.Canceled {
+			t.Errorf("DistStream ended with %v, expected code: %v", err, codes.Canceled)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("DistStream did not end after the client cancelled the stream")
+	}
+
+	// The server keeps serving
+	res, err := client.Dist(context.Background(), &pb.DistRequest{Id: 0, Src: 0, Dest: 2})
+	if err != nil {
+		t.Fatalf("Dist got unexpected error: %v", err)
+	}
+	if res.Result != 2 {
+		t.Errorf("Dist = %v, expected: %v", res.Result, 2)
+	}
+
+	stream, err = client.DistStream(context.Background())
+	if err != nil {
+		t.Fatalf("Error while opening stream: %v\n", err)
+	}
+	if err = stream.Send(&pb.DistRequest{Id: 0, Src: 2, Dest: 0}); err != nil {
+		t.Fatalf("Error while sending request: %v\n", err)
+	}
+	stream.CloseSend()
+
+	streamRes, err := stream.Recv()
+	if err != nil {
+		t.Fatalf("Error while receiving response: %v\n", err)
+	}
+	if streamRes.Result != 2 {
+		t.Errorf("DistStream = %v, expected: %v", streamRes.Result, 2)
+	}
+}
+
 // BenchmarkServer_DistStream serves as the performance testing
 func BenchmarkServer_DistStream(b *testing.B) {
-	idHead = 0
-	graphStore = make(map[int32]Graph)
+	testServer.store = newMemoryStore()
 
 	ctx := context.Background()
 	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
@@ -31,7 +278,7 @@ func BenchmarkServer_DistStream(b *testing.B) {
 	client := pb.NewGraphServiceClient(conn)
 
 	graphs := []struct {
-		totalVertices int32
+		totalVertices int64
 		edgesPb       []*pb.Edge
 	}{
 		{
@@ -90,9 +337,9 @@ func BenchmarkServer_DistStream(b *testing.B) {
 	}
 
 	// 0 1 3 0 0 2 1 0 2 1 4 1 1 3 0 1 4 2 2 3 7 2 2 0 2 6 0 2 1 6 2 2 6 3 0 1 3 0 2 3 2 1
-	ids := []int32{0, 0, 1, 1, 1, 1, 2, 2, 2, 2, 2, 3, 3, 3}
-	srcs := []int32{1, 0, 0, 4, 3, 4, 3, 2, 6, 1, 2, 0, 0, 2}
-	dests := []int32{3, 2, 2, 1, 0, 2, 7, 0, 0, 6, 6, 1, 2, 1}
+	ids := []int64{0, 0, 1, 1, 1, 1, 2, 2, 2, 2, 2, 3, 3, 3}
+	srcs := []int64{1, 0, 0, 4, 3, 4, 3, 2, 6, 1, 2, 0, 0, 2}
+	dests := []int64{3, 2, 2, 1, 0, 2, 7, 0, 0, 6, 6, 1, 2, 1}
 
 	for i := 0; i < b.N; i++ {
 		reqLen := len(ids)
@@ -106,13 +353,13 @@ func BenchmarkServer_DistStream(b *testing.B) {
 
 		go func() {
 			for i := 0; i < reqLen; i++ {
-				req := pb.DistRequest{
+				req := &pb.DistRequest{
 					Id:   ids[i],
 					Src:  srcs[i],
 					Dest: dests[i],
 				}
-				log.Printf("Sending request: %+v\n", req)
-				stream.Send(&req)
+				log.Printf("Sending request: %v\n", req)
+				stream.Send(req)
 			}
 			stream.CloseSend()
 		}()
@@ -144,7 +391,7 @@ func BenchmarkServer_DistStream(b *testing.B) {
 					break
 				}
 
-				if res.Result == math.MaxInt32 {
+				if !res.Reachable {
 					log.Printf("The source node [%d] and destination node [%d] in graph[id=%d] are not connected.\n",
 						res.Src, res.Dest, res.Id)
 				} else {
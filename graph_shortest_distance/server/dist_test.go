@@ -4,17 +4,20 @@ import (
 	"context"
 	"fmt"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
-	"math"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"math/rand"
 	"testing"
+	"time"
 
 	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
 )
 
 // TestServer_Dist tests for computing the shortest distance
 func TestServer_Dist(t *testing.T) {
-	idHead = 0
-	graphStore = make(map[int32]Graph)
+	testServer.store = newMemoryStore()
 
 	ctx := context.Background()
 	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
@@ -28,7 +31,7 @@ func TestServer_Dist(t *testing.T) {
 	client := pb.NewGraphServiceClient(conn)
 
 	graphs := []struct {
-		totalVertices int32
+		totalVertices int64
 		edgesPb       []*pb.Edge
 	}{
 		{
@@ -78,10 +81,11 @@ func TestServer_Dist(t *testing.T) {
 	}
 
 	tests := []struct {
-		expected int32
-		id       int32
-		src      int32
-		dest     int32
+		expected    int64
+		unreachable bool
+		id          int64
+		src         int64
+		dest        int64
 	}{
 		{
 			expected: 2,
@@ -120,10 +124,10 @@ func TestServer_Dist(t *testing.T) {
 			dest:     1,
 		},
 		{
-			expected: math.MaxInt32,
-			id:       2,
-			src:      1,
-			dest:     2,
+			unreachable: true,
+			id:          2,
+			src:         1,
+			dest:        2,
 		},
 		{
 			expected: 0,
@@ -133,6 +137,230 @@ func TestServer_Dist(t *testing.T) {
 		},
 	}
 
+	for _, tt := range tests {
+		res, err := client.Dist(context.Background(), &pb.DistRequest{
+			Id:   tt.id,
+			Src:  tt.src,
+			Dest: tt.dest,
+		})
+
+		if err != nil {
+			t.Errorf("Dist(%+v) got unexpected error", tt)
+		}
+
+		if res.Result != tt.expected || res.Reachable == tt.unreachable {
+			t.Errorf("Dist(%+v) = %v, expected: %v", tt, res, tt.expected)
+		}
+	}
+}
+
+// TestServer_DistWeighted tests for computing the shortest distance in weighted graphs
+func TestServer_DistWeighted(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	graphs := []struct {
+		totalVertices int64
+		edgesPb       []*pb.Edge
+	}{
+		{
+			totalVertices: 5,
+			edgesPb: []*pb.Edge{
+				{Src: 0, Dest: 1, Weight: proto.Int32(4)},
+				{Src: 0, Dest: 2, Weight: proto.Int32(1)},
+				{Src: 2, Dest: 1, Weight: proto.Int32(2)},
+				{Src: 1, Dest: 3, Weight: proto.Int32(5)},
+				{Src: 2, Dest: 3, Weight: proto.Int32(8)},
+				{Src: 3, Dest: 4, Weight: proto.Int32(3)},
+			},
+		},
+		{
+			totalVertices: 4,
+			edgesPb: []*pb.Edge{
+				{Src: 0, Dest: 1, Weight: proto.Int32(0)},
+				{Src: 1, Dest: 2},
+			},
+		},
+	}
+
+	for _, graph := range graphs {
+		_, err := client.Post(context.Background(), &pb.PostRequest{
+			TotalVertices: graph.totalVertices,
+			Edges:         graph.edgesPb,
+		})
+
+		if err != nil {
+			t.Errorf("Post(%+v) got unexpected error", graph)
+		}
+	}
+
+	tests := []struct {
+		expected    int64
+		unreachable bool
+		id          int64
+		src         int64
+		dest        int64
+	}{
+		{
+			expected: 3,
+			id:       0,
+			src:      0,
+			dest:     1,
+		},
+		{
+			expected: 8,
+			id:       0,
+			src:      0,
+			dest:     3,
+		},
+		{
+			expected: 11,
+			id:       0,
+			src:      4,
+			dest:     0,
+		},
+		{
+			expected: 0,
+			id:       0,
+			src:      2,
+			dest:     2,
+		},
+		{
+			expected: 1,
+			id:       1,
+			src:      0,
+			dest:     2,
+		},
+		{
+			unreachable: true,
+			id:          1,
+			src:         0,
+			dest:        3,
+		},
+	}
+
+	for _, tt := range tests {
+		res, err := client.Dist(context.Background(), &pb.DistRequest{
+			Id:   tt.id,
+			Src:  tt.src,
+			Dest: tt.dest,
+		})
+
+		if err != nil {
+			t.Errorf("Dist(%+v) got unexpected error", tt)
+		}
+
+		if res.Result != tt.expected || res.Reachable == tt.unreachable {
+			t.Errorf("Dist(%+v) = %v, expected: %v", tt, res, tt.expected)
+		}
+	}
+}
+
+// TestServer_DistDirected tests for computing the shortest distance in directed graphs
+func TestServer_DistDirected(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	graphs := []struct {
+		totalVertices int64
+		edgesPb       []*pb.Edge
+	}{
+		{
+			totalVertices: 4,
+			edgesPb: []*pb.Edge{
+				{Src: 0, Dest: 1},
+				{Src: 1, Dest: 2},
+				{Src: 2, Dest: 3},
+				{Src: 3, Dest: 0},
+			},
+		},
+		{
+			totalVertices: 3,
+			edgesPb: []*pb.Edge{
+				{Src: 0, Dest: 1, Weight: proto.Int32(1)},
+				{Src: 1, Dest: 2, Weight: proto.Int32(1)},
+				{Src: 0, Dest: 2, Weight: proto.Int32(5)},
+				{Src: 2, Dest: 0, Weight: proto.Int32(3)},
+			},
+		},
+	}
+
+	for _, graph := range graphs {
+		_, err := client.Post(context.Background(), &pb.PostRequest{
+			TotalVertices: graph.totalVertices,
+			Edges:         graph.edgesPb,
+			Directed:      true,
+		})
+
+		if err != nil {
+			t.Errorf("Post(%+v) got unexpected error", graph)
+		}
+	}
+
+	tests := []struct {
+		expected int64
+		id       int64
+		src      int64
+		dest     int64
+	}{
+		{
+			expected: 1,
+			id:       0,
+			src:      0,
+			dest:     1,
+		},
+		{
+			expected: 3,
+			id:       0,
+			src:      1,
+			dest:     0,
+		},
+		{
+			expected: 3,
+			id:       0,
+			src:      0,
+			dest:     3,
+		},
+		{
+			expected: 2,
+			id:       1,
+			src:      0,
+			dest:     2,
+		},
+		{
+			expected: 3,
+			id:       1,
+			src:      2,
+			dest:     0,
+		},
+		{
+			expected: 4,
+			id:       1,
+			src:      1,
+			dest:     0,
+		},
+	}
+
 	for _, tt := range tests {
 		res, err := client.Dist(context.Background(), &pb.DistRequest{
 			Id:   tt.id,
@@ -150,10 +378,96 @@ func TestServer_Dist(t *testing.T) {
 	}
 }
 
+// TestServer_DistDeadline tests that the search gives up once the deadline of the request is exceeded
+func TestServer_DistDeadline(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	// A long path whose ends are far apart, saved in the data store directly since it does not fit in one message
+	const totalVertices = 2_000_000
+	edges := make([]*pb.Edge, totalVertices-1)
+	for i := range edges {
+		edges[i] = &pb.Edge{Src: int64(i), Dest: int64(i + 1)}
+	}
+	graph := newGraph(totalVertices, edges, false)
+	testServer.store.Put(0, graph)
+
+	deadlineCtx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	_, err = client.Dist(deadlineCtx, &pb.DistRequest{Id: 0, Src: 0, Dest: totalVertices - 1})
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("Dist got error %v, expected code: %v", err, codes.DeadlineExceeded)
+	}
+
+	// The search itself must give up, and not only the client
+	_, _, _, err = computeShortestDistance(deadlineCtx, graph, 0, totalVertices-1)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("computeShortestDistance got error %v, expected code: %v", err, codes.DeadlineExceeded)
+	}
+	_, _, err = getBidirectionalDistance(deadlineCtx, totalVertices, 0, totalVertices-1, graph.adj)
+	if status.Code(err) != codes.DeadlineExceeded {
+		t.Errorf("getBidirectionalDistance got error %v, expected code: %v", err, codes.DeadlineExceeded)
+	}
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	weightedGraph := newGraph(totalVertices, append(edges, &pb.Edge{Src: 0, Dest: 1, Weight: proto.Int32(2)}), false)
+	_, _, _, err = computeShortestDistance(cancelledCtx, weightedGraph, 0, totalVertices-1)
+	if status.Code(err) != codes.Canceled {
+		t.Errorf("computeShortestDistance got error %v, expected code: %v", err, codes.Canceled)
+	}
+}
+
+// TestBidirectionalDistance_MatchesBFS tests that the bidirectional BFS finds the same shortest distances as the BFS
+// from the source node alone, on random undirected graphs ranging from mostly disconnected to dense
+func TestBidirectionalDistance_MatchesBFS(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for seed := int64(0); seed < 200; seed++ {
+		totalVertices := 1 + r.Int63n(300)
+		totalEdges := r.Intn(3 * int(totalVertices))
+		graph := newGraph(totalVertices, randomEdges(totalVertices, totalEdges, seed), false)
+
+		for i := 0; i < 20; i++ {
+			src := r.Int63n(totalVertices)
+			dest := r.Int63n(totalVertices)
+
+			expected, expectedReachable, _, err := getShortestDistance(context.Background(), totalVertices, src, dest,
+				graph.adj)
+			if err != nil {
+				t.Fatalf("getShortestDistance got unexpected error: %v", err)
+			}
+
+			distance, reachable, err := getBidirectionalDistance(context.Background(), totalVertices, src, dest,
+				graph.adj)
+			if err != nil {
+				t.Fatalf("getBidirectionalDistance got unexpected error: %v", err)
+			}
+
+			if distance != expected || reachable != expectedReachable {
+				t.Fatalf("Graph #%d (%d vertices, %d edges): getBidirectionalDistance(%d, %d) = %d (reachable: %v), "+
+					"expected: %d (reachable: %v)", seed, totalVertices, totalEdges, src, dest, distance, reachable,
+					expected, expectedReachable)
+			}
+		}
+	}
+}
+
 // TestServer_DistInvalidInput tests for invalid parameters
 func TestServer_DistInvalidInput(t *testing.T) {
-	idHead = 0
-	graphStore = make(map[int32]Graph)
+	testServer.store = newMemoryStore()
 
 	ctx := context.Background()
 	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
@@ -167,7 +481,7 @@ func TestServer_DistInvalidInput(t *testing.T) {
 	client := pb.NewGraphServiceClient(conn)
 
 	graphs := []struct {
-		totalVertices int32
+		totalVertices int64
 		edgesPb       []*pb.Edge
 	}{
 		{
@@ -289,8 +603,7 @@ func TestServer_DistInvalidInput(t *testing.T) {
 
 // BenchmarkServer_Dist serves as the performance testing
 func BenchmarkServer_Dist(b *testing.B) {
-	idHead = 0
-	graphStore = make(map[int32]Graph)
+	testServer.store = newMemoryStore()
 
 	ctx := context.Background()
 	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
@@ -304,7 +617,7 @@ func BenchmarkServer_Dist(b *testing.B) {
 	client := pb.NewGraphServiceClient(conn)
 
 	graphs := []struct {
-		totalVertices int32
+		totalVertices int64
 		edgesPb       []*pb.Edge
 	}{
 		{
@@ -354,10 +667,10 @@ func BenchmarkServer_Dist(b *testing.B) {
 	}
 
 	tests := []struct {
-		distance int32
-		id       int32
-		src      int32
-		dest     int32
+		distance int64
+		id       int64
+		src      int64
+		dest     int64
 	}{
 		{
 			distance: 0,
@@ -409,3 +722,91 @@ func BenchmarkServer_Dist(b *testing.B) {
 		})
 	}
 }
+
+// BenchmarkShortestDistance_Bidirectional compares the queries on a large graph of a low diameter computed by the
+// bidirectional BFS, as Dist does for undirected unweighted graphs, against the BFS from the source node alone
+func BenchmarkShortestDistance_Bidirectional(b *testing.B) {
+	const totalVertices = 250_000
+	graph := newGraph(totalVertices, randomEdges(totalVertices, 1_000_000, 1), false)
+
+	b.Run("bidirectional_bfs", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			src := int64(i % totalVertices)
+			dest := int64((i + totalVertices/2) % totalVertices)
+			getBidirectionalDistance(context.Background(), totalVertices, src, dest, graph.adj)
+		}
+	})
+
+	b.Run("bfs", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			src := int64(i % totalVertices)
+			dest := int64((i + totalVertices/2) % totalVertices)
+			getShortestDistance(context.Background(), totalVertices, src, dest, graph.adj)
+		}
+	})
+}
+
+// randomEdges generates the given number of random edges between the given number of vertices
+func randomEdges(totalVertices int64, totalEdges int, seed int64) []*pb.Edge {
+	r := rand.New(rand.NewSource(seed))
+
+	edges := make([]*pb.Edge, totalEdges)
+	for i := range edges {
+		edges[i] = &pb.Edge{Src: r.Int63n(totalVertices), Dest: r.Int63n(totalVertices)}
+	}
+	return edges
+}
+
+// BenchmarkServer_DistLargeGraph serves as the performance testing on a graph with a million edges. The graph is saved
+// in the data store directly, since it does not fit in the default maximum message size of gRPC.
+func BenchmarkServer_DistLargeGraph(b *testing.B) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		b.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	const totalVertices = 250_000
+	testServer.store.Put(0, newGraph(totalVertices, randomEdges(totalVertices, 1_000_000, 1), false))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		client.Dist(context.Background(), &pb.DistRequest{
+			Id:   0,
+			Src:  int64(i % totalVertices),
+			Dest: int64((i + totalVertices/2) % totalVertices),
+		})
+	}
+}
+
+// BenchmarkShortestDistance_LargeGraph compares the cost of a query on a graph with a million edges using the
+// adjacency structure cached in the graph, against rebuilding the adjacency structure for every query
+func BenchmarkShortestDistance_LargeGraph(b *testing.B) {
+	const totalVertices = 250_000
+	edges := randomEdges(totalVertices, 1_000_000, 1)
+	graph := newGraph(totalVertices, edges, false)
+
+	b.Run("cached_adjacency", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			src := int64(i % totalVertices)
+			dest := int64((i + totalVertices/2) % totalVertices)
+			getShortestDistance(context.Background(), totalVertices, src, dest, graph.adj)
+		}
+	})
+
+	b.Run("rebuilt_adjacency", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			src := int64(i % totalVertices)
+			dest := int64((i + totalVertices/2) % totalVertices)
+			adj := buildAdjacency(totalVertices, edges, false, false)
+			getShortestDistance(context.Background(), totalVertices, src, dest, adj)
+		}
+	})
+}
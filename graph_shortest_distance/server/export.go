@@ -0,0 +1,246 @@
+package main
+
+import (
+	"bufio"
+	"encoding/xml"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"log"
+	"strings"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// exportChunkSize is the maximum number of bytes sent in a single Export response
+const exportChunkSize = 1 << 16
+
+// Export serializes the graph associated with the specified ID to GraphML or DOT, and streams the result back in
+// chunks. When a highlight is requested, one shortest path between its two nodes is computed the same way as in Path,
+// and its vertices and edges are marked in the exported graph.
+func (s *Server) Export(req *pb.ExportRequest, stream pb.GraphService_ExportServer) error {
+	log.Printf("Export was invoked with: %v\n", req)
+
+	graph, err := s.queryGraph(req.Id)
+	if err != nil {
+		return err
+	}
+
+	var write func(w io.Writer, graph Graph, id int64, path exportPath)
+	switch req.Format {
+	case pb.GraphFormat_GRAPH_FORMAT_GRAPHML:
+		write = writeGraphML
+	case pb.GraphFormat_GRAPH_FORMAT_DOT:
+		write = writeDOT
+	default:
+		return status.Errorf(
+			codes.InvalidArgument,
+			fmt.Sprintf("Unsupported export format: %v", req.Format),
+		)
+	}
+
+	var path exportPath
+	if req.Highlight != nil {
+		src, err := queryNode(graph, req.Highlight.Src, req.Highlight.SrcLabel, "source")
+		if err != nil {
+			return err
+		}
+
+		dest, err := queryNode(graph, req.Highlight.Dest, req.Highlight.DestLabel, "destination")
+		if err != nil {
+			return err
+		}
+
+		_, reachable, parent, err := computeShortestDistance(stream.Context(), graph, src, dest)
+		if err != nil {
+			return err
+		}
+
+		// Nothing is highlighted if the two nodes are not connected
+		if reachable {
+			path = newExportPath(graph, buildPath(parent, src, dest))
+		}
+	}
+
+	sw := &exportStreamWriter{stream: stream}
+	bw := bufio.NewWriterSize(sw, exportChunkSize)
+	write(bw, graph, req.Id, path)
+	if err := bw.Flush(); err != nil {
+		log.Printf("Error while sending data to client: %v\n", err)
+		return status.Errorf(
+			status.Code(err),
+			fmt.Sprintf("Error while sending data to client: %v", err),
+		)
+	}
+
+	return nil
+}
+
+// exportStreamWriter sends everything written to it as Export responses
+type exportStreamWriter struct {
+	stream pb.GraphService_ExportServer
+}
+
+func (sw *exportStreamWriter) Write(p []byte) (int, error) {
+	if err := sw.stream.Send(&pb.ExportResponse{Data: p}); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// exportPath marks the vertices and edges of the highlighted path in an exported graph
+type exportPath struct {
+	// vertices is the set of the vertices along the path
+	vertices map[int64]bool
+	// edges is the set of the indexes of the graph edges along the path
+	edges map[int]bool
+}
+
+// newExportPath finds the graph edges traversed by the path going through the given vertices. When several edges
+// connect the same pair of vertices, only one of those with the lowest weight is marked, the same as in Path.
+func newExportPath(graph Graph, vertices []int64) exportPath {
+	path := exportPath{vertices: make(map[int64]bool), edges: make(map[int]bool)}
+	for _, vertex := range vertices {
+		path.vertices[vertex] = true
+	}
+
+	// steps maps every [src -> dest] step of the path to the index of the step
+	steps := make(map[[2]int64]int)
+	for i := 0; i+1 < len(vertices); i++ {
+		steps[[2]int64{vertices[i], vertices[i+1]}] = i
+	}
+	pathEdges := buildPathEdges(graph, vertices)
+	found := make([]bool, len(pathEdges))
+
+	for i, edge := range graph.edges {
+		step, ok := steps[[2]int64{edge.Src, edge.Dest}]
+		if !ok && !graph.directed {
+			step, ok = steps[[2]int64{edge.Dest, edge.Src}]
+		}
+		if !ok || found[step] || (graph.weighted && edgeWeight(edge) != *pathEdges[step].Weight) {
+			continue
+		}
+
+		found[step] = true
+		path.edges[i] = true
+	}
+
+	return path
+}
+
+// writeGraphML writes the graph in the GraphML format. The vertex n is given the ID "n<n>", and the weight, the
+// highlighting and the vertex labels of a labeled graph are given as GraphML attributes.
+func writeGraphML(w io.Writer, graph Graph, id int64, path exportPath) {
+	edgeDefault := "undirected"
+	if graph.directed {
+		edgeDefault = "directed"
+	}
+
+	fmt.Fprintln(w, `<?xml version="1.0" encoding="UTF-8"?>`)
+	fmt.Fprintln(w, `<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`)
+	fmt.Fprintln(w, `  <key id="weight" for="edge" attr.name="weight" attr.type="int"><default>1</default></key>`)
+	fmt.Fprintln(w, `  <key id="node_path" for="node" attr.name="path" attr.type="boolean"><default>false</default></key>`)
+	fmt.Fprintln(w, `  <key id="edge_path" for="edge" attr.name="path" attr.type="boolean"><default>false</default></key>`)
+	if graph.labels != nil {
+		fmt.Fprintln(w, `  <key id="label" for="node" attr.name="label" attr.type="string"/>`)
+	}
+	fmt.Fprintf(w, "  <graph id=\"graph%d\" edgedefault=\"%s\">\n", id, edgeDefault)
+
+	for vertex := int64(0); vertex < graph.totalVertices; vertex++ {
+		var data string
+		if graph.labels != nil {
+			data = fmt.Sprintf("<data key=\"label\">%s</data>", xmlEscape(graph.labels.labels[vertex]))
+		}
+		if path.vertices[vertex] {
+			data += "<data key=\"node_path\">true</data>"
+		}
+
+		if data == "" {
+			fmt.Fprintf(w, "    <node id=\"n%d\"/>\n", vertex)
+		} else {
+			fmt.Fprintf(w, "    <node id=\"n%d\">%s</node>\n", vertex, data)
+		}
+	}
+
+	for i, edge := range graph.edges {
+		fmt.Fprintf(w, "    <edge id=\"e%d\" source=\"n%d\" target=\"n%d\"", i, edge.Src, edge.Dest)
+		if edge.Weight == nil && !path.edges[i] {
+			fmt.Fprintln(w, "/>")
+			continue
+		}
+
+		fmt.Fprint(w, ">")
+		if edge.Weight != nil {
+			fmt.Fprintf(w, "<data key=\"weight\">%d</data>", *edge.Weight)
+		}
+		if path.edges[i] {
+			fmt.Fprint(w, "<data key=\"edge_path\">true</data>")
+		}
+		fmt.Fprintln(w, "</edge>")
+	}
+
+	fmt.Fprintln(w, "  </graph>")
+	fmt.Fprintln(w, "</graphml>")
+}
+
+// writeDOT writes the graph in the DOT language of Graphviz. The weights are given as edge labels, the vertex labels of
+// a labeled graph as node labels, and the highlighted vertices and edges are drawn in red.
+func writeDOT(w io.Writer, graph Graph, id int64, path exportPath) {
+	graphType, edgeOp := "graph", "--"
+	if graph.directed {
+		graphType, edgeOp = "digraph", "->"
+	}
+
+	fmt.Fprintf(w, "%s graph%d {\n", graphType, id)
+
+	for vertex := int64(0); vertex < graph.totalVertices; vertex++ {
+		var attrs string
+		if graph.labels != nil {
+			attrs = fmt.Sprintf("label=\"%s\"", dotEscaper.Replace(graph.labels.labels[vertex]))
+		}
+		if path.vertices[vertex] {
+			if attrs != "" {
+				attrs += ", "
+			}
+			attrs += "color=red, penwidth=2"
+		}
+
+		if attrs == "" {
+			fmt.Fprintf(w, "  %d;\n", vertex)
+		} else {
+			fmt.Fprintf(w, "  %d [%s];\n", vertex, attrs)
+		}
+	}
+
+	for i, edge := range graph.edges {
+		var attrs string
+		if edge.Weight != nil {
+			attrs = fmt.Sprintf("label=%d", *edge.Weight)
+		}
+		if path.edges[i] {
+			if attrs != "" {
+				attrs += ", "
+			}
+			attrs += "color=red, penwidth=2"
+		}
+
+		if attrs == "" {
+			fmt.Fprintf(w, "  %d %s %d;\n", edge.Src, edgeOp, edge.Dest)
+		} else {
+			fmt.Fprintf(w, "  %d %s %d [%s];\n", edge.Src, edgeOp, edge.Dest, attrs)
+		}
+	}
+
+	fmt.Fprintln(w, "}")
+}
+
+// dotEscaper escapes the special characters of a DOT quoted string
+var dotEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// xmlEscape escapes the special XML characters of the text
+func xmlEscape(text string) string {
+	var sb strings.Builder
+	_ = xml.EscapeText(&sb, []byte(text))
+	return sb.String()
+}
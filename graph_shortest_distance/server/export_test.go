@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"strings"
+	"testing"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// exportGraph returns the whole file exported by Export
+func exportGraph(client pb.GraphServiceClient, req *pb.ExportRequest) (string, error) {
+	stream, err := client.Export(context.Background(), req)
+	if err != nil {
+		return "", err
+	}
+
+	var content strings.Builder
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			return content.String(), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		content.Write(res.Data)
+	}
+}
+
+// TestServer_Export tests for exporting graphs to GraphML and DOT, with and without a highlighted path
+func TestServer_Export(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	graphs := []*pb.PostRequest{
+		{
+			TotalVertices: 4,
+			Edges: []*pb.Edge{
+				{Src: 0, Dest: 1},
+				{Src: 2, Dest: 1},
+				{Src: 0, Dest: 2},
+			},
+		},
+		{
+			TotalVertices: 3,
+			Edges: []*pb.Edge{
+				{Src: 0, Dest: 1, Weight: proto.Int32(5)},
+				{Src: 0, Dest: 1, Weight: proto.Int32(1)},
+				{Src: 1, Dest: 2, Weight: proto.Int32(1)},
+				{Src: 0, Dest: 2, Weight: proto.Int32(3)},
+			},
+			Directed: true,
+		},
+		{
+			Edges: []*pb.Edge{
+				{SrcLabel: `say "hi"`, DestLabel: "a<b"},
+			},
+		},
+	}
+
+	for _, graph := range graphs {
+		if _, err := client.Post(context.Background(), graph); err != nil {
+			t.Fatalf("Post(%v) got unexpected error: %v", graph, err)
+		}
+	}
+
+	tests := []struct {
+		req      *pb.ExportRequest
+		expected string
+	}{
+		{
+			req: &pb.ExportRequest{Id: 0, Format: pb.GraphFormat_GRAPH_FORMAT_DOT},
+			expected: "graph graph0 {\n" +
+				"  0;\n" +
+				"  1;\n" +
+				"  2;\n" +
+				"  3;\n" +
+				"  0 -- 1;\n" +
+				"  2 -- 1;\n" +
+				"  0 -- 2;\n" +
+				"}\n",
+		},
+		{
+			// The path 1 -> 2 traverses the edge 2 -- 1 backwards
+			req: &pb.ExportRequest{
+				Id:        0,
+				Format:    pb.GraphFormat_GRAPH_FORMAT_DOT,
+				Highlight: &pb.PathHighlight{Src: 1, Dest: 2},
+			},
+			expected: "graph graph0 {\n" +
+				"  0;\n" +
+				"  1 [color=red, penwidth=2];\n" +
+				"  2 [color=red, penwidth=2];\n" +
+				"  3;\n" +
+				"  0 -- 1;\n" +
+				"  2 -- 1 [color=red, penwidth=2];\n" +
+				"  0 -- 2;\n" +
+				"}\n",
+		},
+		{
+			// Nothing is highlighted, since the nodes are not connected
+			req: &pb.ExportRequest{
+				Id:        0,
+				Format:    pb.GraphFormat_GRAPH_FORMAT_DOT,
+				Highlight: &pb.PathHighlight{Src: 0, Dest: 3},
+			},
+			expected: "graph graph0 {\n" +
+				"  0;\n" +
+				"  1;\n" +
+				"  2;\n" +
+				"  3;\n" +
+				"  0 -- 1;\n" +
+				"  2 -- 1;\n" +
+				"  0 -- 2;\n" +
+				"}\n",
+		},
+		{
+			// Only the lightest of the parallel edges is highlighted
+			req: &pb.ExportRequest{
+				Id:        1,
+				Format:    pb.GraphFormat_GRAPH_FORMAT_DOT,
+				Highlight: &pb.PathHighlight{Src: 0, Dest: 2},
+			},
+			expected: "digraph graph1 {\n" +
+				"  0 [color=red, penwidth=2];\n" +
+				"  1 [color=red, penwidth=2];\n" +
+				"  2 [color=red, penwidth=2];\n" +
+				"  0 -> 1 [label=5];\n" +
+				"  0 -> 1 [label=1, color=red, penwidth=2];\n" +
+				"  1 -> 2 [label=1, color=red, penwidth=2];\n" +
+				"  0 -> 2 [label=3];\n" +
+				"}\n",
+		},
+		{
+			req: &pb.ExportRequest{
+				Id:        1,
+				Format:    pb.GraphFormat_GRAPH_FORMAT_GRAPHML,
+				Highlight: &pb.PathHighlight{Src: 1, Dest: 2},
+			},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n" +
+				`  <key id="weight" for="edge" attr.name="weight" attr.type="int"><default>1</default></key>` + "\n" +
+				`  <key id="node_path" for="node" attr.name="path" attr.type="boolean"><default>false</default>` +
+				`</key>` + "\n" +
+				`  <key id="edge_path" for="edge" attr.name="path" attr.type="boolean"><default>false</default>` +
+				`</key>` + "\n" +
+				`  <graph id="graph1" edgedefault="directed">` + "\n" +
+				`    <node id="n0"/>` + "\n" +
+				`    <node id="n1"><data key="node_path">true</data></node>` + "\n" +
+				`    <node id="n2"><data key="node_path">true</data></node>` + "\n" +
+				`    <edge id="e0" source="n0" target="n1"><data key="weight">5</data></edge>` + "\n" +
+				`    <edge id="e1" source="n0" target="n1"><data key="weight">1</data></edge>` + "\n" +
+				`    <edge id="e2" source="n1" target="n2"><data key="weight">1</data>` +
+				`<data key="edge_path">true</data></edge>` + "\n" +
+				`    <edge id="e3" source="n0" target="n2"><data key="weight">3</data></edge>` + "\n" +
+				`  </graph>` + "\n" +
+				`</graphml>` + "\n",
+		},
+		{
+			// The labels of a labeled graph are escaped
+			req: &pb.ExportRequest{
+				Id:        2,
+				Format:    pb.GraphFormat_GRAPH_FORMAT_DOT,
+				Highlight: &pb.PathHighlight{SrcLabel: "a<b", DestLabel: "a<b"},
+			},
+			expected: "graph graph2 {\n" +
+				`  0 [label="say \"hi\""];` + "\n" +
+				`  1 [label="a<b", color=red, penwidth=2];` + "\n" +
+				"  0 -- 1;\n" +
+				"}\n",
+		},
+		{
+			req: &pb.ExportRequest{Id: 2, Format: pb.GraphFormat_GRAPH_FORMAT_GRAPHML},
+			expected: `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+				`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">` + "\n" +
+				`  <key id="weight" for="edge" attr.name="weight" attr.type="int"><default>1</default></key>` + "\n" +
+				`  <key id="node_path" for="node" attr.name="path" attr.type="boolean"><default>false</default>` +
+				`</key>` + "\n" +
+				`  <key id="edge_path" for="edge" attr.name="path" attr.type="boolean"><default>false</default>` +
+				`</key>` + "\n" +
+				`  <key id="label" for="node" attr.name="label" attr.type="string"/>` + "\n" +
+				`  <graph id="graph2" edgedefault="undirected">` + "\n" +
+				`    <node id="n0"><data key="label">say &#34;hi&#34;</data></node>` + "\n" +
+				`    <node id="n1"><data key="label">a&lt;b</data></node>` + "\n" +
+				`    <edge id="e0" source="n0" target="n1"/>` + "\n" +
+				`  </graph>` + "\n" +
+				`</graphml>` + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		content, err := exportGraph(client, tt.req)
+
+		if err != nil {
+			t.Fatalf("Export(%v) got unexpected error: %v", tt.req, err)
+		}
+
+		if content != tt.expected {
+			t.Errorf("Export(%v) = %s, expected: %s", tt.req, content, tt.expected)
+		}
+	}
+
+	// Invalid parameters
+	invalidTests := []struct {
+		req  *pb.ExportRequest
+		code codes.Code
+	}{
+		{
+			req:  &pb.ExportRequest{Id: 3, Format: pb.GraphFormat_GRAPH_FORMAT_DOT},
+			code: codes.NotFound,
+		},
+		{
+			req:  &pb.ExportRequest{Id: 0},
+			code: codes.InvalidArgument,
+		},
+		{
+			req:  &pb.ExportRequest{Id: 0, Format: pb.GraphFormat_GRAPH_FORMAT_DIMACS},
+			code: codes.InvalidArgument,
+		},
+		{
+			req: &pb.ExportRequest{
+				Id:        0,
+				Format:    pb.GraphFormat_GRAPH_FORMAT_DOT,
+				Highlight: &pb.PathHighlight{Src: 0, Dest: 4},
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			req: &pb.ExportRequest{
+				Id:        0,
+				Format:    pb.GraphFormat_GRAPH_FORMAT_GRAPHML,
+				Highlight: &pb.PathHighlight{Src: -1, Dest: 0},
+			},
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range invalidTests {
+		content, err := exportGraph(client, tt.req)
+
+		if err == nil {
+			t.Fatal("Failed to catch expected error\n")
+		}
+
+		if status.Code(err) != tt.code {
+			t.Errorf("Export(%v) = %s, %v, expected code: %v", tt.req, content, err, tt.code)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"log"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// Import posts a new graph from a file uploaded over a stream in chunks of bytes. The format of the file is given by
+// the first message of the stream. The graph is only saved once the whole file has been uploaded and parsed.
+// DIMACS files describe directed graphs, whose vertices 1 to n are mapped onto the vertices 0 to n - 1.
+// Returns the new graph's unique ID for future reference.
+func (s *Server) Import(stream pb.GraphService_ImportServer) error {
+	log.Println("Import was invoked")
+
+	r := &importReader{stream: stream}
+	first, err := r.recv()
+	if err == io.EOF {
+		return status.Error(codes.InvalidArgument, "The stream was closed before the file was sent")
+	}
+	if err != nil {
+		return err
+	}
+
+	if first.Format != pb.GraphFormat_GRAPH_FORMAT_DIMACS {
+		return status.Errorf(
+			codes.InvalidArgument,
+			fmt.Sprintf("Unsupported import format: %v", first.Format),
+		)
+	}
+
+	// The first message may already carry the start of the file
+	r.data = first.Data
+	totalVertices, edges, err := readDIMACS(r, s.maxVertices)
+
+	// The stream itself failed, rather than the file being invalid
+	if r.err != nil && r.err != io.EOF {
+		return r.err
+	}
+	if err != nil {
+		return status.Errorf(
+			codes.InvalidArgument,
+			fmt.Sprintf("Invalid DIMACS file: %v", err),
+		)
+	}
+
+	// Saving the graph
+	currId, err := s.saveNewGraph(newGraph(totalVertices, edges, true))
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Import received %d vertices and %d edges for the graph[id=%d]\n", totalVertices, len(edges), currId)
+
+	return stream.SendAndClose(&pb.PostResponse{Result: currId})
+}
+
+// importReader reads the content of the file uploaded over an Import stream
+type importReader struct {
+	stream pb.GraphService_ImportServer
+	// data is the part of the last chunk not read yet
+	data []byte
+	// err is the error which ended the stream, which is io.EOF once the client closes it
+	err error
+}
+
+func (r *importReader) Read(p []byte) (int, error) {
+	for len(r.data) == 0 {
+		if r.err != nil {
+			return 0, r.err
+		}
+
+		req, err := r.recv()
+		if err != nil {
+			return 0, err
+		}
+		r.data = req.Data
+	}
+
+	n := copy(p, r.data)
+	r.data = r.data[n:]
+	return n, nil
+}
+
+// recv receives the next message of the stream
+func (r *importReader) recv() (*pb.ImportRequest, error) {
+	req, err := r.stream.Recv()
+
+	if err == io.EOF {
+		r.err = err
+		return nil, err
+	}
+
+	if err != nil {
+		log.Printf("Error while reading client stream: %v\n", err)
+		r.err = status.Errorf(
+			status.Code(err),
+			fmt.Sprintf("Error while reading client stream: %v", err),
+		)
+		return nil, r.err
+	}
+
+	return req, nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"testing"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// importFile uploads the file content with Import, in chunks of the given size
+func importFile(client pb.GraphServiceClient, format pb.GraphFormat, content string,
+	chunkSize int) (*pb.PostResponse, error) {
+	stream, err := client.Import(context.Background())
+	if err != nil {
+		return nil, err
+	}
+
+	err = stream.Send(&pb.ImportRequest{Format: format})
+	for start := 0; err == nil && start < len(content); start += chunkSize {
+		end := start + chunkSize
+		if end > len(content) {
+			end = len(content)
+		}
+		err = stream.Send(&pb.ImportRequest{Data: []byte(content[start:end])})
+	}
+
+	return stream.CloseAndRecv()
+}
+
+// TestServer_Import tests for importing a DIMACS graph, sent in chunks which split its lines
+func TestServer_Import(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	content := "c 9th DIMACS Implementation Challenge: Shortest Paths\n" +
+		"c\n" +
+		"p sp 4 5\n" +
+		"a 1 2 4\n" +
+		"a 1 3 1\n" +
+		"\n" +
+		"a 3 2 2\n" +
+		"a 2 4 1\n" +
+		"a 4 1 0\n"
+
+	res, err := importFile(client, pb.GraphFormat_GRAPH_FORMAT_DIMACS, content, 7)
+	if err != nil {
+		t.Fatalf("Import got unexpected error: %v", err)
+	}
+
+	graph, ok := testServer.store.Get(res.Result)
+	if !ok {
+		t.Fatalf("The graph[id=%d] does not exist in the data store", res.Result)
+	}
+
+	expected := []*pb.Edge{
+		{Src: 0, Dest: 1, Weight: proto.Int32(4)},
+		{Src: 0, Dest: 2, Weight: proto.Int32(1)},
+		{Src: 2, Dest: 1, Weight: proto.Int32(2)},
+		{Src: 1, Dest: 3, Weight: proto.Int32(1)},
+		{Src: 3, Dest: 0, Weight: proto.Int32(0)},
+	}
+
+	if graph.totalVertices != 4 || !graph.directed || len(graph.edges) != len(expected) {
+		t.Fatalf("Import saved %d vertices and %d edges, directed: %v, expected: 4 vertices and %d edges, directed",
+			graph.totalVertices, len(graph.edges), graph.directed, len(expected))
+	}
+	for i := range expected {
+		if !proto.Equal(graph.edges[i], expected[i]) {
+			t.Errorf("Import saved edge #%d = %v, expected: %v", i, graph.edges[i], expected[i])
+		}
+	}
+
+	tests := []struct {
+		expected int64
+		src      int64
+		dest     int64
+	}{
+		{expected: 4, src: 0, dest: 3},
+		{expected: 1, src: 3, dest: 2},
+		{expected: 3, src: 0, dest: 1},
+	}
+
+	for _, tt := range tests {
+		distRes, err := client.Dist(context.Background(), &pb.DistRequest{Id: res.Result, Src: tt.src, Dest: tt.dest})
+
+		if err != nil {
+			t.Fatalf("Dist(%+v) got unexpected error: %v", tt, err)
+		}
+
+		if distRes.Result != tt.expected {
+			t.Errorf("Dist(%+v) = %v, expected: %v", tt, distRes.Result, tt.expected)
+		}
+	}
+}
+
+// TestServer_ImportInvalidInput tests for invalid files, which must not save anything
+func TestServer_ImportInvalidInput(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	tests := []struct {
+		format  pb.GraphFormat
+		content string
+	}{
+		// The format is not specified
+		{format: pb.GraphFormat_GRAPH_FORMAT_UNSPECIFIED, content: "p sp 2 1\na 1 2 1\n"},
+		// The problem line is missing
+		{format: pb.GraphFormat_GRAPH_FORMAT_DIMACS, content: "c empty\n"},
+		// The arc is given before the problem line
+		{format: pb.GraphFormat_GRAPH_FORMAT_DIMACS, content: "a 1 2 1\np sp 2 1\n"},
+		// Not a shortest path problem
+		{format: pb.GraphFormat_GRAPH_FORMAT_DIMACS, content: "p max 2 1\na 1 2 1\n"},
+		// Duplicated problem line
+		{format: pb.GraphFormat_GRAPH_FORMAT_DIMACS, content: "p sp 2 1\np sp 2 1\na 1 2 1\n"},
+		// Vertex 0 does not exist, since the DIMACS vertices are numbered from 1
+		{format: pb.GraphFormat_GRAPH_FORMAT_DIMACS, content: "p sp 2 1\na 0 1 1\n"},
+		// Vertex n + 1 does not exist
+		{format: pb.GraphFormat_GRAPH_FORMAT_DIMACS, content: "p sp 2 1\na 1 3 1\n"},
+		// Negative weight
+		{format: pb.GraphFormat_GRAPH_FORMAT_DIMACS, content: "p sp 2 1\na 1 2 -1\n"},
+		// Missing weight
+		{format: pb.GraphFormat_GRAPH_FORMAT_DIMACS, content: "p sp 2 1\na 1 2\n"},
+		// The number of arcs does not match the problem line
+		{format: pb.GraphFormat_GRAPH_FORMAT_DIMACS, content: "p sp 2 2\na 1 2 1\n"},
+		// Too many vertices for the adjacency structure to be allocated
+		{format: pb.GraphFormat_GRAPH_FORMAT_DIMACS, content: "p sp 9223372036854775807 0\n"},
+		// Unknown line type
+		{format: pb.GraphFormat_GRAPH_FORMAT_DIMACS, content: "p sp 2 1\ne 1 2\n"},
+	}
+
+	for _, tt := range tests {
+		res, err := importFile(client, tt.format, tt.content, 1024)
+
+		if err == nil {
+			t.Fatal("Failed to catch expected error\n")
+		}
+
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("Import(%q) = %v, %v, expected code: %v", tt.content, res, err, codes.InvalidArgument)
+		}
+	}
+
+	if ids := testServer.store.List(); len(ids) != 0 {
+		t.Errorf("List() = %v, expected no graph", ids)
+	}
+}
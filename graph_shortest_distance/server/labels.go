@@ -0,0 +1,240 @@
+package main
+
+import (
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// labelDictionary maps the string labels of the vertices of a labeled graph to their dense indexes, so that the
+// queries run on the same integer structures as the unlabeled graphs.
+// A dictionary is never modified once its graph is saved. Mutations work on a clone instead.
+type labelDictionary struct {
+	// labels is the label of every vertex, in index order
+	labels []string
+	// indexes maps every label to its vertex index
+	indexes map[string]int64
+}
+
+// newLabelDictionary creates a dictionary holding the given labels, in the same order
+func newLabelDictionary(labels []string) (*labelDictionary, error) {
+	d := &labelDictionary{indexes: make(map[string]int64, len(labels))}
+	if err := d.addVertices(labels); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// clone returns a copy of the dictionary, which can be modified without affecting the original one
+func (d *labelDictionary) clone() *labelDictionary {
+	c := &labelDictionary{
+		labels:  make([]string, len(d.labels)),
+		indexes: make(map[string]int64, len(d.indexes)),
+	}
+	copy(c.labels, d.labels)
+	for label, index := range d.indexes {
+		c.indexes[label] = index
+	}
+	return c
+}
+
+// addVertices appends new vertices having the given labels, which must not exist in the dictionary yet
+func (d *labelDictionary) addVertices(labels []string) error {
+	for _, label := range labels {
+		if label == "" {
+			return status.Error(codes.InvalidArgument, "Invalid vertex label: must not be empty.")
+		}
+		if _, ok := d.indexes[label]; ok {
+			return status.Errorf(
+				codes.InvalidArgument,
+				fmt.Sprintf("The vertex label [%s] is given more than once", label),
+			)
+		}
+		d.add(label)
+	}
+
+	return nil
+}
+
+// validateSize checks that the dictionary does not give more vertices than the maximum total number of vertices of
+// the server, the same as the total number of vertices of an unlabeled graph
+func (d *labelDictionary) validateSize(maxVertices int64) error {
+	if int64(len(d.labels)) > maxVertices {
+		return status.Errorf(
+			codes.InvalidArgument,
+			fmt.Sprintf("Invalid total number of vertices: %d labels are given. Must not exceed %d.",
+				len(d.labels), maxVertices),
+		)
+	}
+
+	return nil
+}
+
+// add returns the index of the vertex having the label, appending a new vertex if the label does not exist yet
+func (d *labelDictionary) add(label string) int64 {
+	index, ok := d.indexes[label]
+	if !ok {
+		index = int64(len(d.labels))
+		d.labels = append(d.labels, label)
+		d.indexes[label] = index
+	}
+	return index
+}
+
+// indexEdges converts the labeled edges to edges between vertex indexes. The labels which do not exist in the
+// dictionary yet are added as new vertices if addMissing is set, and are reported as codes.NotFound otherwise.
+func (d *labelDictionary) indexEdges(edges []*pb.Edge, addMissing bool) ([]*pb.Edge, error) {
+	indexed := make([]*pb.Edge, len(edges))
+	for i, edge := range edges {
+		if edge.SrcLabel == "" || edge.DestLabel == "" {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				fmt.Sprintf("The edge [%q -> %q] must give the labels of both its nodes, since the graph is labeled",
+					edge.SrcLabel, edge.DestLabel),
+			)
+		}
+
+		src, err := d.lookup(edge.SrcLabel, addMissing)
+		if err != nil {
+			return nil, err
+		}
+
+		dest, err := d.lookup(edge.DestLabel, addMissing)
+		if err != nil {
+			return nil, err
+		}
+
+		indexed[i] = &pb.Edge{Src: src, Dest: dest, Weight: edge.Weight}
+	}
+
+	return indexed, nil
+}
+
+// lookup returns the index of the vertex having the label. A missing label is added as a new vertex if addMissing is
+// set, and is reported as codes.NotFound otherwise.
+func (d *labelDictionary) lookup(label string, addMissing bool) (int64, error) {
+	if addMissing {
+		return d.add(label), nil
+	}
+
+	index, ok := d.indexes[label]
+	if !ok {
+		return 0, status.Errorf(
+			codes.NotFound,
+			fmt.Sprintf("The node [%s] does not exist in the graph", label),
+		)
+	}
+	return index, nil
+}
+
+// labelEdges sets the labels of the nodes of the edges, which are given by their indexes
+func (d *labelDictionary) labelEdges(edges []*pb.Edge) {
+	for _, edge := range edges {
+		edge.SrcLabel = d.labels[edge.Src]
+		edge.DestLabel = d.labels[edge.Dest]
+	}
+}
+
+// hasLabels returns whether any of the edges gives a node by its label
+func hasLabels(edges []*pb.Edge) bool {
+	for _, edge := range edges {
+		if edge.SrcLabel != "" || edge.DestLabel != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// indexGraphEdges converts the edges of a mutation of the graph to edges between vertex indexes, and returns them
+// along with the dictionary of the mutated graph. In a labeled graph, the edges must give their nodes by labels, and
+// the labels which do not exist yet are added as new vertices to a clone of the dictionary if addMissing is set.
+// In an unlabeled graph, the edges are returned as is, and the dictionary is nil.
+func indexGraphEdges(graph Graph, edges []*pb.Edge, addMissing bool) ([]*pb.Edge, *labelDictionary, error) {
+	if graph.labels == nil {
+		if hasLabels(edges) {
+			return nil, nil, status.Error(codes.InvalidArgument,
+				"The nodes of the edges are given by labels, but the graph is not labeled")
+		}
+		return edges, nil, nil
+	}
+
+	labels := graph.labels
+	if addMissing {
+		labels = labels.clone()
+	}
+
+	indexed, err := labels.indexEdges(edges, addMissing)
+	if err != nil {
+		return nil, nil, err
+	}
+	return indexed, labels, nil
+}
+
+// newLabeledGraph creates a labeled graph from the vertex labels and the labeled edges. The vertices listed in labels
+// come first, followed by the vertices of the edges whose labels are not listed, in order of first appearance.
+// The graph must not have more than maxVertices vertices.
+func newLabeledGraph(labels []string, edges []*pb.Edge, directed bool, maxVertices int64) (Graph, error) {
+	dict, err := newLabelDictionary(labels)
+	if err != nil {
+		return Graph{}, err
+	}
+
+	indexed, err := dict.indexEdges(edges, true)
+	if err != nil {
+		return Graph{}, err
+	}
+
+	if err = dict.validateSize(maxVertices); err != nil {
+		return Graph{}, err
+	}
+
+	// The nodes are valid by construction, but the weights must be checked
+	if err = validateEdges(int64(len(dict.labels)), indexed); err != nil {
+		return Graph{}, err
+	}
+
+	graph := newGraph(int64(len(dict.labels)), indexed, directed)
+	graph.labels = dict
+	return graph, nil
+}
+
+// validateLabeledTotalVertices checks that the total number of vertices of a new labeled graph is not given, since it
+// is given by the labels
+func validateLabeledTotalVertices(totalVertices int64) error {
+	if totalVertices != 0 {
+		return status.Errorf(
+			codes.InvalidArgument,
+			fmt.Sprintf("Invalid total number of vertices: %d. Must be 0 for a labeled graph, whose vertices are "+
+				"given by the labels.", totalVertices),
+		)
+	}
+
+	return nil
+}
+
+// resolveNode returns the index of a node of the graph, which is given by its label if the label is set, or by its
+// index otherwise. The kind of the node ("source" or "destination") is used in the error message.
+// The index is not validated, since it is checked the same way for the labeled and unlabeled graphs.
+func resolveNode(graph Graph, index int64, label string, kind string) (int64, error) {
+	if label == "" {
+		return index, nil
+	}
+
+	if graph.labels == nil {
+		return 0, status.Errorf(
+			codes.InvalidArgument,
+			fmt.Sprintf("The %s node [%s] is given by its label, but the graph is not labeled", kind, label),
+		)
+	}
+
+	index, ok := graph.labels.indexes[label]
+	if !ok {
+		return 0, status.Errorf(
+			codes.InvalidArgument,
+			fmt.Sprintf("The %s node [%s] does not exist in the graph", kind, label),
+		)
+	}
+	return index, nil
+}
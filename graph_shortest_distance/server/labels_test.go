@@ -0,0 +1,444 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"reflect"
+	"testing"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// TestServer_Labels tests for posting, querying and modifying a graph whose vertices are given by labels
+func TestServer_Labels(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	// The vertices listed first come first, followed by the other vertices of the edges in order of appearance
+	res, err := client.Post(context.Background(), &pb.PostRequest{
+		Labels: []string{"db.internal", "isolated"},
+		Edges: []*pb.Edge{
+			{SrcLabel: "web-1", DestLabel: "lb", Weight: proto.Int32(2)},
+			{SrcLabel: "lb", DestLabel: "db.internal", Weight: proto.Int32(3)},
+			{SrcLabel: "web-1", DestLabel: "db.internal", Weight: proto.Int32(9)},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Post got unexpected error: %v", err)
+	}
+
+	id := res.Result
+
+	path, err := client.Path(context.Background(), &pb.DistRequest{Id: id, SrcLabel: "web-1", DestLabel: "db.internal"})
+	if err != nil {
+		t.Fatalf("Path got unexpected error: %v", err)
+	}
+
+	expectedLabels := []string{"web-1", "lb", "db.internal"}
+	expectedEdges := []*pb.Edge{
+		{Src: 2, Dest: 3, SrcLabel: "web-1", DestLabel: "lb", Weight: proto.Int32(2)},
+		{Src: 3, Dest: 0, SrcLabel: "lb", DestLabel: "db.internal", Weight: proto.Int32(3)},
+	}
+	if path.Result != 5 || !reflect.DeepEqual(path.Vertices, []int64{2, 3, 0}) ||
+		!reflect.DeepEqual(path.VertexLabels, expectedLabels) {
+		t.Errorf("Path = %v, expected distance 5 through %v", path, expectedLabels)
+	}
+	for i := range expectedEdges {
+		if i >= len(path.Edges) || !proto.Equal(path.Edges[i], expectedEdges[i]) {
+			t.Errorf("Path edges = %v, expected: %v", path.Edges, expectedEdges)
+			break
+		}
+	}
+
+	// Mutations give the nodes by labels too, and new labels add new vertices
+	mutations := []func() (*pb.MutationResponse, error){
+		func() (*pb.MutationResponse, error) {
+			return client.AddEdges(context.Background(), &pb.AddEdgesRequest{
+				Id:    id,
+				Edges: []*pb.Edge{{SrcLabel: "isolated", DestLabel: "web-2"}},
+			})
+		},
+		func() (*pb.MutationResponse, error) {
+			return client.AddVertices(context.Background(), &pb.AddVerticesRequest{
+				Id:     id,
+				Labels: []string{"spare"},
+			})
+		},
+		func() (*pb.MutationResponse, error) {
+			return client.RemoveEdges(context.Background(), &pb.RemoveEdgesRequest{
+				Id:    id,
+				Edges: []*pb.Edge{{SrcLabel: "db.internal", DestLabel: "lb"}},
+			})
+		},
+	}
+
+	for i, mutate := range mutations {
+		if _, err := mutate(); err != nil {
+			t.Fatalf("Mutation #%d got unexpected error: %v", i, err)
+		}
+	}
+
+	tests := []struct {
+		src         string
+		dest        string
+		expected    int64
+		unreachable bool
+	}{
+		{src: "web-1", dest: "db.internal", expected: 9},
+		{src: "isolated", dest: "web-2", expected: 1},
+		{src: "spare", dest: "spare", expected: 0},
+		{src: "spare", dest: "web-1", unreachable: true},
+	}
+
+	for i, tt := range tests {
+		res, err := client.Dist(context.Background(), &pb.DistRequest{Id: id, SrcLabel: tt.src, DestLabel: tt.dest})
+
+		if err != nil {
+			t.Errorf("Test #%d: Dist got unexpected error: %v", i, err)
+			continue
+		}
+
+		if res.Result != tt.expected || res.Reachable == tt.unreachable {
+			t.Errorf("Test #%d: Dist(%s, %s) = %v, expected: %d", i, tt.src, tt.dest, res, tt.expected)
+		}
+	}
+
+	// The labels are listed and read back in index order, before the edges
+	list, err := client.ListGraphs(context.Background(), &pb.ListGraphsRequest{})
+	if err != nil {
+		t.Fatalf("ListGraphs got unexpected error: %v", err)
+	}
+	if len(list.Graphs) != 1 || !list.Graphs[0].Labeled || list.Graphs[0].TotalVertices != 6 {
+		t.Errorf("ListGraphs = %v, expected a single labeled graph having 6 vertices", list.Graphs)
+	}
+
+	stream, err := client.GetGraph(context.Background(), &pb.GetGraphRequest{Id: id})
+	if err != nil {
+		t.Fatalf("GetGraph got unexpected error: %v", err)
+	}
+
+	var labels []string
+	var totalEdges int
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("GetGraph got unexpected error: %v", err)
+		}
+		if len(res.Labels) > 0 && totalEdges > 0 {
+			t.Errorf("GetGraph sent labels after the edges")
+		}
+		labels = append(labels, res.Labels...)
+		totalEdges += len(res.Edges)
+	}
+
+	expectedLabels = []string{"db.internal", "isolated", "web-1", "lb", "web-2", "spare"}
+	if !reflect.DeepEqual(labels, expectedLabels) || totalEdges != 3 {
+		t.Errorf("GetGraph = %v and %d edges, expected: %v and %d edges", labels, totalEdges, expectedLabels, 3)
+	}
+}
+
+// TestServer_LabelsInvalidInput tests for the requests mixing labeled and unlabeled graphs or giving invalid labels
+func TestServer_LabelsInvalidInput(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	labeled, err := client.Post(context.Background(), &pb.PostRequest{
+		Edges: []*pb.Edge{{SrcLabel: "a", DestLabel: "b"}},
+	})
+	if err != nil {
+		t.Fatalf("Post got unexpected error: %v", err)
+	}
+
+	unlabeled, err := client.Post(context.Background(), &pb.PostRequest{
+		TotalVertices: 2,
+		Edges:         []*pb.Edge{{Src: 0, Dest: 1}},
+	})
+	if err != nil {
+		t.Fatalf("Post got unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		call     func() error
+		expected codes.Code
+	}{
+		{
+			name: "labeled graph given a total number of vertices",
+			call: func() error {
+				_, err := client.Post(context.Background(), &pb.PostRequest{
+					TotalVertices: 2,
+					Edges:         []*pb.Edge{{SrcLabel: "a", DestLabel: "b"}},
+				})
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name: "edge missing a label",
+			call: func() error {
+				_, err := client.Post(context.Background(), &pb.PostRequest{
+					Edges: []*pb.Edge{{SrcLabel: "a", DestLabel: "b"}, {SrcLabel: "a", Dest: 1}},
+				})
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name: "duplicate vertex label",
+			call: func() error {
+				_, err := client.Post(context.Background(), &pb.PostRequest{Labels: []string{"a", "b", "a"}})
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name: "unknown label",
+			call: func() error {
+				_, err := client.Dist(context.Background(), &pb.DistRequest{
+					Id:        labeled.Result,
+					SrcLabel:  "a",
+					DestLabel: "c",
+				})
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name: "label on an unlabeled graph",
+			call: func() error {
+				_, err := client.Path(context.Background(), &pb.DistRequest{
+					Id:       unlabeled.Result,
+					SrcLabel: "a",
+				})
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name: "labeled edges added to an unlabeled graph",
+			call: func() error {
+				_, err := client.AddEdges(context.Background(), &pb.AddEdgesRequest{
+					Id:    unlabeled.Result,
+					Edges: []*pb.Edge{{SrcLabel: "a", DestLabel: "b"}},
+				})
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name: "removed edge of an unknown label",
+			call: func() error {
+				_, err := client.RemoveEdges(context.Background(), &pb.RemoveEdgesRequest{
+					Id:    labeled.Result,
+					Edges: []*pb.Edge{{SrcLabel: "a", DestLabel: "c"}},
+				})
+				return err
+			},
+			expected: codes.NotFound,
+		},
+		{
+			name: "existing vertex label added",
+			call: func() error {
+				_, err := client.AddVertices(context.Background(), &pb.AddVerticesRequest{
+					Id:     labeled.Result,
+					Labels: []string{"b"},
+				})
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name: "vertex labels added to an unlabeled graph",
+			call: func() error {
+				_, err := client.AddVertices(context.Background(), &pb.AddVerticesRequest{
+					Id:     unlabeled.Result,
+					Labels: []string{"c"},
+				})
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.call()
+
+		if err == nil {
+			t.Fatal("Failed to catch expected error\n")
+		}
+
+		if code := status.Code(err); code != tt.expected {
+			t.Errorf("%s: got error code %v, expected: %v", tt.name, code, tt.expected)
+		}
+	}
+}
+
+// TestServer_LabelsMaxVertices tests that the vertices added by labels are bounded by the maximum total number of
+// vertices of the server, the same as the vertices of an unlabeled graph
+func TestServer_LabelsMaxVertices(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	defer func(maxVertices int64) { testServer.maxVertices = maxVertices }(testServer.maxVertices)
+	testServer.maxVertices = 3
+
+	labeled, err := client.Post(context.Background(), &pb.PostRequest{
+		Labels: []string{"a"},
+		Edges:  []*pb.Edge{{SrcLabel: "b", DestLabel: "c"}},
+	})
+	if err != nil {
+		t.Fatalf("Post got unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		call     func() error
+		expected codes.Code
+	}{
+		{
+			name: "too many labels posted",
+			call: func() error {
+				_, err := client.Post(context.Background(), &pb.PostRequest{
+					Labels: []string{"a", "b"},
+					Edges:  []*pb.Edge{{SrcLabel: "c", DestLabel: "d"}},
+				})
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name: "too many labels streamed",
+			call: func() error {
+				stream, err := client.PostStream(context.Background())
+				if err != nil {
+					return err
+				}
+
+				upload := []*pb.PostStreamRequest{
+					{Data: &pb.PostStreamRequest_Header{Header: &pb.PostStreamHeader{Labeled: true}}},
+					{Data: &pb.PostStreamRequest_Edges{Edges: &pb.EdgeChunk{Labels: []string{"a", "b"}}}},
+					{Data: &pb.PostStreamRequest_Edges{Edges: &pb.EdgeChunk{
+						Edges: []*pb.Edge{{SrcLabel: "c", DestLabel: "d"}},
+					}}},
+				}
+				for _, req := range upload {
+					// Sending fails once the server ended the stream, and the error is then returned by CloseAndRecv
+					if err = stream.Send(req); err != nil {
+						break
+					}
+				}
+
+				_, err = stream.CloseAndRecv()
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name: "too many labels added as vertices",
+			call: func() error {
+				_, err := client.AddVertices(context.Background(), &pb.AddVerticesRequest{
+					Id:     labeled.Result,
+					Labels: []string{"d"},
+				})
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+		{
+			name: "too many labels added by edges",
+			call: func() error {
+				_, err := client.AddEdges(context.Background(), &pb.AddEdgesRequest{
+					Id:    labeled.Result,
+					Edges: []*pb.Edge{{SrcLabel: "a", DestLabel: "d"}},
+				})
+				return err
+			},
+			expected: codes.InvalidArgument,
+		},
+	}
+
+	for _, tt := range tests {
+		err := tt.call()
+
+		if err == nil {
+			t.Fatal("Failed to catch expected error\n")
+		}
+
+		if code := status.Code(err); code != tt.expected {
+			t.Errorf("%s: got error code %v, expected: %v", tt.name, code, tt.expected)
+		}
+	}
+
+	if ids := testServer.store.List(); len(ids) != 1 {
+		t.Errorf("List() = %v, expected: %v", ids, []int64{labeled.Result})
+	}
+	if graph, _ := testServer.store.Get(labeled.Result); graph.totalVertices != 3 {
+		t.Errorf("The graph has %d vertices after the invalid mutations, expected: %d", graph.totalVertices, 3)
+	}
+}
+
+// TestGraphFile_Labels tests that the vertex labels are kept by the binary format of the graphs
+func TestGraphFile_Labels(t *testing.T) {
+	graph, err := newLabeledGraph([]string{"solo"}, []*pb.Edge{{SrcLabel: "x", DestLabel: "y"}}, true,
+		defaultMaxVertices)
+	if err != nil {
+		t.Fatalf("newLabeledGraph got unexpected error: %v", err)
+	}
+
+	var buf bytes.Buffer
+	if err = encodeGraphFile(&buf, graph); err != nil {
+		t.Fatalf("encodeGraphFile got unexpected error: %v", err)
+	}
+
+	decoded, err := decodeGraphFile(&buf)
+	if err != nil {
+		t.Fatalf("decodeGraphFile got unexpected error: %v", err)
+	}
+
+	if decoded.labels == nil || !reflect.DeepEqual(decoded.labels.labels, graph.labels.labels) {
+		t.Fatalf("decodeGraphFile got labels %v, expected: %v", decoded.labels, graph.labels.labels)
+	}
+	if index := decoded.labels.indexes["y"]; index != 2 {
+		t.Errorf("decodeGraphFile got index %d for label y, expected: %d", index, 2)
+	}
+}
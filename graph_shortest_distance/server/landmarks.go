@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"math"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// unreachableDistance is the distance recorded between a landmark and a vertex which are not connected
+const unreachableDistance = math.MaxInt64
+
+// landmarkDistances keeps the shortest distances between a few landmark vertices and every vertex of a graph, from
+// which the triangle inequality gives lower bounds of the shortest distance between any two vertices
+type landmarkDistances struct {
+	vertices []int64
+	// from[i][v] is the shortest distance from the ith landmark to the vertex v, and to[i][v] the shortest distance
+	// from the vertex v to the ith landmark, which is the same as from[i][v] in an undirected graph
+	from [][]int64
+	to   [][]int64
+}
+
+// selectLandmarks picks up to the given number of landmarks in the graph, and computes their shortest distances.
+// Every landmark is the vertex furthest from the landmarks picked before it, starting from the vertex furthest from
+// the first vertex having an edge, so that the landmarks lie at the edges of the graph, where their lower bounds are
+// the tightest. The vertices not reachable from the landmarks are the furthest, so that every component of the graph
+// gets a landmark. Fewer landmarks are picked once every vertex having an edge is at distance 0 from one of them, but
+// at least one is always picked.
+// The graph must have at least one edge.
+func selectLandmarks(ctx context.Context, graph Graph, count int) (*landmarkDistances, error) {
+	reversed := reverseGraph(graph)
+
+	seed := int64(0)
+	for len(graph.adj.neighbours(seed)) == 0 && len(reversed.adj.neighbours(seed)) == 0 {
+		seed++
+	}
+
+	// closest[v] is the shortest distance from the closest landmark to the vertex v, starting with the seed
+	closest, err := distancesFrom(ctx, graph, seed)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &landmarkDistances{}
+	for len(l.vertices) < count {
+		furthest := int64(-1)
+		for v := int64(0); v < graph.totalVertices; v++ {
+			if len(graph.adj.neighbours(v)) == 0 && len(reversed.adj.neighbours(v)) == 0 {
+				continue
+			}
+			// The first landmark is picked even at distance 0 from the seed, since the graph needs one
+			if (closest[v] > 0 || len(l.vertices) == 0) && (furthest < 0 || closest[v] > closest[furthest]) {
+				furthest = v
+			}
+		}
+		if furthest < 0 {
+			break
+		}
+
+		if err := l.add(ctx, graph, reversed, furthest); err != nil {
+			return nil, err
+		}
+
+		// The distances from the seed are only used for picking the first landmark
+		if len(l.vertices) == 1 {
+			copy(closest, l.from[0])
+			continue
+		}
+		for v, dist := range l.from[len(l.vertices)-1] {
+			if dist < closest[v] {
+				closest[v] = dist
+			}
+		}
+	}
+
+	return l, nil
+}
+
+// newLandmarkDistances computes the shortest distances of the given landmarks of the graph
+func newLandmarkDistances(ctx context.Context, graph Graph, vertices []int64) (*landmarkDistances, error) {
+	reversed := reverseGraph(graph)
+
+	l := &landmarkDistances{}
+	for _, vertex := range vertices {
+		if err := l.add(ctx, graph, reversed, vertex); err != nil {
+			return nil, err
+		}
+	}
+	return l, nil
+}
+
+// add computes the shortest distances from and to the vertex, and adds it as a new landmark. The distances to the
+// vertex are the distances from it in the reversed graph.
+func (l *landmarkDistances) add(ctx context.Context, graph Graph, reversed Graph, vertex int64) error {
+	from, err := distancesFrom(ctx, graph, vertex)
+	if err != nil {
+		return err
+	}
+
+	to := from
+	if graph.directed {
+		if to, err = distancesFrom(ctx, reversed, vertex); err != nil {
+			return err
+		}
+	}
+
+	l.vertices = append(l.vertices, vertex)
+	l.from = append(l.from, from)
+	l.to = append(l.to, to)
+	return nil
+}
+
+// lowerBound returns a lower bound of the shortest distance from the vertex to the destination node, and false if the
+// destination node is certainly not reachable from the vertex.
+// For every landmark L, the triangle inequality gives d(L, dest) - d(L, node) <= d(node, dest) and
+// d(node, L) - d(dest, L) <= d(node, dest), and the best of these bounds is returned. If L reaches the vertex but not
+// the destination node, or the destination node reaches L but the vertex does not, then the vertex cannot reach the
+// destination node either.
+func (l *landmarkDistances) lowerBound(node int64, dest int64) (int64, bool) {
+	bound := int64(0)
+	for i := range l.vertices {
+		from, to := l.from[i], l.to[i]
+
+		if from[node] != unreachableDistance {
+			if from[dest] == unreachableDistance {
+				return 0, false
+			}
+			if d := from[dest] - from[node]; d > bound {
+				bound = d
+			}
+		}
+
+		if to[dest] != unreachableDistance {
+			if to[node] == unreachableDistance {
+				return 0, false
+			}
+			if d := to[node] - to[dest]; d > bound {
+				bound = d
+			}
+		}
+	}
+	return bound, true
+}
+
+// distancesFrom returns the shortest distances from the source node to every vertex of the graph, which are
+// unreachableDistance for the vertices not reachable from it
+func distancesFrom(ctx context.Context, graph Graph, src int64) ([]int64, error) {
+	order, dist, err := computeDistancesFrom(ctx, graph, src, math.MaxInt64)
+	if err != nil {
+		return nil, err
+	}
+
+	distances := make([]int64, graph.totalVertices)
+	for i := range distances {
+		distances[i] = unreachableDistance
+	}
+	for _, vertex := range order {
+		distances[vertex] = dist[vertex]
+	}
+	return distances, nil
+}
+
+// reverseGraph returns the graph whose edges are reversed, in which the shortest distances from a vertex are the
+// shortest distances to the vertex in a directed graph. An undirected graph is returned as is.
+func reverseGraph(graph Graph) Graph {
+	if !graph.directed {
+		return graph
+	}
+
+	edges := make([]*pb.Edge, len(graph.edges))
+	for i, edge := range graph.edges {
+		edges[i] = &pb.Edge{Src: edge.Dest, Dest: edge.Src, Weight: edge.Weight}
+	}
+	return newGraph(graph.totalVertices, edges, true)
+}
@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"log"
+	"sort"
+	"strconv"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// defaultListPageSize is the number of graphs returned by ListGraphs when the request does not specify a page size
+const defaultListPageSize = 100
+
+// maxListPageSize is the maximum number of graphs returned by ListGraphs at once
+const maxListPageSize = 1000
+
+// ListGraphs returns one page of the graphs in the data store, in ascending order of ID.
+// The page token is the ID the page starts from, so the pages stay consistent while graphs are posted or deleted.
+func (s *Server) ListGraphs(ctx context.Context, req *pb.ListGraphsRequest) (*pb.ListGraphsResponse, error) {
+	log.Printf("ListGraphs was invoked with: %v\n", req)
+
+	// Parameter validation
+	if req.PageSize < 0 {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			fmt.Sprintf("Invalid page size: %d. Must not be negative.", req.PageSize),
+		)
+	}
+
+	pageSize := int(req.PageSize)
+	if pageSize == 0 {
+		pageSize = defaultListPageSize
+	} else if pageSize > maxListPageSize {
+		pageSize = maxListPageSize
+	}
+
+	var start int64
+	if req.PageToken != "" {
+		token, err := strconv.ParseInt(req.PageToken, 10, 64)
+		if err != nil || token < 0 {
+			return nil, status.Errorf(
+				codes.InvalidArgument,
+				fmt.Sprintf("Invalid page token: %q", req.PageToken),
+			)
+		}
+		start = token
+	}
+
+	ids := s.store.List()
+	i := sort.Search(len(ids), func(i int) bool { return ids[i] >= start })
+
+	res := &pb.ListGraphsResponse{}
+	for ; i < len(ids) && len(res.Graphs) < pageSize; i++ {
+		graph, ok := s.store.Get(ids[i])
+
+		// The graph has been deleted since the IDs were listed
+		if !ok {
+			continue
+		}
+
+		info := &pb.GraphInfo{
+			Id:            ids[i],
+			TotalVertices: graph.totalVertices,
+			TotalEdges:    int64(len(graph.edges)),
+			Directed:      graph.directed,
+			Labeled:       graph.labels != nil,
+			Preprocessed:  graph.landmarks != nil,
+		}
+		if graph.landmarks != nil {
+			info.Landmarks = int32(len(graph.landmarks.vertices))
+		}
+		if !graph.createdAt.IsZero() {
+			info.CreatedAt = timestamppb.New(graph.createdAt)
+		}
+		res.Graphs = append(res.Graphs, info)
+	}
+
+	if i < len(ids) {
+		res.NextPageToken = strconv.FormatInt(ids[i], 10)
+	}
+
+	return res, nil
+}
+
+// GetGraph streams back the vertices and edges of the graph associated with the specified ID. The edges are sent in
+// chunks of at most maxChunkBytes, so that graphs of any size can be read back. The vertex labels of a labeled graph
+// are sent in chunks as well, before the edges.
+func (s *Server) GetGraph(req *pb.GetGraphRequest, stream pb.GraphService_GetGraphServer) error {
+	log.Printf("GetGraph was invoked with: %v\n", req)
+
+	graph, ok := s.store.Get(req.Id)
+
+	// The graph does not exist in the data store
+	if !ok {
+		return status.Errorf(
+			codes.NotFound,
+			fmt.Sprintf("The graph[id=%d] does not exist in the data store", req.Id),
+		)
+	}
+
+	res := &pb.GetGraphResponse{
+		TotalVertices: graph.totalVertices,
+		TotalEdges:    int64(len(graph.edges)),
+		Directed:      graph.directed,
+		Labeled:       graph.labels != nil,
+	}
+
+	sent := false
+	if graph.labels != nil {
+		labels := graph.labels.labels
+		for start, end := 0, 0; start < len(labels); start = end {
+			end = nextChunkEnd(start, len(labels), func(i int) int { return stringFieldSize(labels[i]) })
+			res.Labels = labels[start:end]
+
+			if err := sendGraphChunk(stream, res); err != nil {
+				return err
+			}
+			sent = true
+			res = &pb.GetGraphResponse{}
+		}
+	}
+
+	// At least one response is sent even if the graph has no edges, since the first one carries the graph totals
+	for start, end := 0, 0; !sent || start < len(graph.edges); start = end {
+		end = nextChunkEnd(start, len(graph.edges), func(i int) int { return messageFieldSize(graph.edges[i]) })
+		res.Edges = graph.edges[start:end]
+
+		if err := sendGraphChunk(stream, res); err != nil {
+			return err
+		}
+		sent = true
+		res = &pb.GetGraphResponse{}
+	}
+
+	return nil
+}
+
+// sendGraphChunk sends a single GetGraph response
+func sendGraphChunk(stream pb.GraphService_GetGraphServer, res *pb.GetGraphResponse) error {
+	if err := stream.Send(res); err != nil {
+		return status.Errorf(
+			status.Code(err),
+			fmt.Sprintf("Error while sending data to client: %v", err),
+		)
+	}
+	return nil
+}
@@ -0,0 +1,270 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// TestServer_ListGraphs tests for listing the graphs page by page
+func TestServer_ListGraphs(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	before := time.Now()
+	for i := int64(0); i < 5; i++ {
+		_, err := client.Post(context.Background(), &pb.PostRequest{
+			TotalVertices: i + 2,
+			Edges: []*pb.Edge{
+				{Src: 0, Dest: i + 1},
+			},
+			Directed: i%2 == 0,
+		})
+
+		if err != nil {
+			t.Fatalf("Post got unexpected error: %v", err)
+		}
+	}
+	after := time.Now()
+
+	if _, err = client.Delete(context.Background(), &pb.DeleteRequest{Id: 2}); err != nil {
+		t.Fatalf("Delete got unexpected error: %v", err)
+	}
+
+	var pages [][]int64
+	pageToken := ""
+	for {
+		res, err := client.ListGraphs(context.Background(), &pb.ListGraphsRequest{
+			PageSize:  2,
+			PageToken: pageToken,
+		})
+
+		if err != nil {
+			t.Fatalf("ListGraphs got unexpected error: %v", err)
+		}
+
+		var ids []int64
+		for _, info := range res.Graphs {
+			ids = append(ids, info.Id)
+
+			if info.TotalVertices != info.Id+2 || info.TotalEdges != 1 || info.Directed != (info.Id%2 == 0) {
+				t.Errorf("ListGraphs returned %v, expected %d vertices, 1 edge and directed = %v", info,
+					info.Id+2, info.Id%2 == 0)
+			}
+
+			if createdAt := info.CreatedAt.AsTime(); createdAt.Before(before) || createdAt.After(after) {
+				t.Errorf("ListGraphs returned %v, expected creation time between %v and %v", info, before, after)
+			}
+		}
+		pages = append(pages, ids)
+
+		if res.NextPageToken == "" {
+			break
+		}
+		pageToken = res.NextPageToken
+	}
+
+	expected := [][]int64{{0, 1}, {3, 4}}
+	if !reflect.DeepEqual(pages, expected) {
+		t.Errorf("ListGraphs pages = %v, expected: %v", pages, expected)
+	}
+
+	// Invalid parameters
+	reqs := []*pb.ListGraphsRequest{
+		{PageSize: -1},
+		{PageToken: "abc"},
+		{PageToken: "-1"},
+	}
+
+	for _, req := range reqs {
+		res, err := client.ListGraphs(context.Background(), req)
+
+		if err == nil {
+			t.Fatal("Failed to catch expected error\n")
+		}
+
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("ListGraphs(%v) = %v, %v, expected code: %v", req, res, err, codes.InvalidArgument)
+		}
+	}
+}
+
+// TestServer_GetGraph tests for reading back the graphs, including a graph whose edges span several responses
+func TestServer_GetGraph(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	graphs := []*pb.PostRequest{
+		{
+			TotalVertices: 3,
+			Edges: []*pb.Edge{
+				{Src: 0, Dest: 1, Weight: proto.Int32(4)},
+				{Src: 1, Dest: 2},
+			},
+			Directed: true,
+		},
+		{
+			TotalVertices: 5,
+		},
+		{
+			TotalVertices: 1000,
+			Edges:         randomEdges(1000, 200000, 1),
+		},
+	}
+
+	for i, graph := range graphs {
+		_, err := client.Post(context.Background(), graph)
+
+		if err != nil {
+			t.Fatalf("Post got unexpected error: %v", err)
+		}
+
+		stream, err := client.GetGraph(context.Background(), &pb.GetGraphRequest{Id: int64(i)})
+
+		if err != nil {
+			t.Fatalf("GetGraph got unexpected error: %v", err)
+		}
+
+		var responses []*pb.GetGraphResponse
+		for {
+			res, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				t.Fatalf("GetGraph got unexpected error: %v", err)
+			}
+			responses = append(responses, res)
+		}
+
+		if len(responses) == 0 {
+			t.Fatalf("GetGraph(%d) returned no response", i)
+		}
+
+		first := responses[0]
+		if first.TotalVertices != graph.TotalVertices || first.TotalEdges != int64(len(graph.Edges)) ||
+			first.Directed != graph.Directed {
+			t.Errorf("GetGraph(%d) = %v, expected: %v", i, first, graph)
+		}
+
+		var edges []*pb.Edge
+		for _, res := range responses {
+			edges = append(edges, res.Edges...)
+		}
+
+		if len(edges) != len(graph.Edges) {
+			t.Fatalf("GetGraph(%d) returned %d edges, expected: %d", i, len(edges), len(graph.Edges))
+		}
+		for j := range edges {
+			if !proto.Equal(edges[j], graph.Edges[j]) {
+				t.Errorf("GetGraph(%d) edge #%d = %v, expected: %v", i, j, edges[j], graph.Edges[j])
+			}
+		}
+
+		// The first response also carries the totals of the graph
+		for j, res := range responses {
+			if size := proto.Size(res); size > maxChunkBytes+64 {
+				t.Errorf("GetGraph(%d) response #%d has %d bytes, expected at most: %d", i, j, size, maxChunkBytes)
+			}
+		}
+		expected := proto.Size(&pb.GetGraphResponse{Edges: graph.Edges})/maxChunkBytes + 1
+		if len(responses) != expected {
+			t.Errorf("GetGraph(%d) sent %d responses, expected: %d", i, len(responses), expected)
+		}
+	}
+
+	// The graph does not exist
+	stream, err := client.GetGraph(context.Background(), &pb.GetGraphRequest{Id: 3})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+
+	if err == nil {
+		t.Fatal("Failed to catch expected error\n")
+	}
+
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("GetGraph(3) = %v, expected code: %v", err, codes.NotFound)
+	}
+}
+
+// TestServer_GetGraphLongLabels tests that the labels of a labeled graph are sent in chunks small enough for the
+// default gRPC message size limit, however long they are
+func TestServer_GetGraphLongLabels(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	// 65,536 labels of 64 bytes would not fit in a single message
+	labels := make([]string, 100000)
+	for i := range labels {
+		labels[i] = fmt.Sprintf("%064d", i)
+	}
+	dict, err := newLabelDictionary(labels)
+	if err != nil {
+		t.Fatalf("newLabelDictionary got unexpected error: %v", err)
+	}
+	graph := newGraph(int64(len(labels)), randomEdges(int64(len(labels)), 1000, 1), false)
+	graph.labels = dict
+	testServer.store.Put(0, graph)
+
+	stream, err := client.GetGraph(context.Background(), &pb.GetGraphRequest{Id: 0})
+	if err != nil {
+		t.Fatalf("GetGraph got unexpected error: %v", err)
+	}
+
+	var received []string
+	for {
+		res, err := stream.Recv()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatalf("GetGraph got unexpected error: %v", err)
+		}
+		received = append(received, res.Labels...)
+	}
+
+	if !reflect.DeepEqual(received, labels) {
+		t.Errorf("GetGraph returned %d labels, expected: %d", len(received), len(labels))
+	}
+}
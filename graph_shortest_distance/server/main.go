@@ -1,17 +1,93 @@
 package main
 
 import (
+	"flag"
 	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
 	"google.golang.org/grpc"
 	"log"
 	"net"
+	"os"
+	"os/signal"
+	"runtime"
+	"syscall"
+	"time"
 )
 
 var addr string = "0.0.0.0:50051"
 
 func main() {
-	idHead = 0
-	graphStore = make(map[int32]Graph)
+	storeType := flag.String("store", "memory", "Specify one of the following data store backends: "+
+		"memory/disk.\n"+
+		"memory - keep the graphs in memory only, so they are lost when the server exits.\n"+
+		"disk - keep the graphs on disk under the directory given by -data-dir, so they survive a restart.")
+	dataDir := flag.String("data-dir", "data", "The directory used by the disk data store")
+	snapshotFile := flag.String("snapshot-file", "", "Used with the memory data store. The file where the "+
+		"snapshots of all graphs are saved periodically and on shutdown, and restored from on startup. "+
+		"Snapshots are disabled if it is empty.")
+	snapshotInterval := flag.Duration("snapshot-interval", 5*time.Minute, "The interval between two "+
+		"periodic snapshots")
+	walFile := flag.String("wal-file", "", "Used with the memory data store. The write-ahead log file where "+
+		"every Post and Delete is recorded before it is acknowledged, replayed on startup on top of the snapshot, "+
+		"and compacted once a new snapshot is saved. The write-ahead log is disabled if it is empty.")
+	streamWorkers := flag.Int("stream-workers", runtime.NumCPU(), "The number of requests computed in "+
+		"parallel within a single stream of shortest distance requests, and the number of rows computed in parallel "+
+		"within a single distance matrix")
+	maxVertices := flag.Int64("max-vertices", defaultMaxVertices, "The maximum total number of vertices of a "+
+		"graph posted, imported or grown by a client. The graphs already stored are loaded whatever their size.")
+	flag.Parse()
+
+	if *maxVertices < 1 {
+		log.Fatalf("Invalid maximum total number of vertices: %d. Must be positive.\n", *maxVertices)
+	}
+
+	var store GraphStore
+	var snapshots *snapshotter
+	switch *storeType {
+	case "memory":
+		memoryStore := newMemoryStore()
+
+		if *snapshotFile != "" {
+			restored, err := loadSnapshot(*snapshotFile)
+			if err == nil {
+				log.Printf("Restored %d graphs from snapshot %s\n", len(restored.List()), *snapshotFile)
+				memoryStore = restored
+			} else if os.IsNotExist(err) {
+				log.Printf("No snapshot found at %s, starting with an empty data store\n", *snapshotFile)
+			} else {
+				log.Fatalf("Failed to restore snapshot %s: %v\n", *snapshotFile, err)
+			}
+
+			snapshots = &snapshotter{store: memoryStore, path: *snapshotFile}
+		}
+
+		store = memoryStore
+
+		if *walFile != "" {
+			wal, err := openWAL(memoryStore, *walFile)
+			if err != nil {
+				log.Fatalf("Failed to open the write-ahead log %s: %v\n", *walFile, err)
+			}
+			defer wal.Close()
+
+			if snapshots != nil {
+				snapshots.wal = wal
+			}
+			store = wal
+		}
+	case "disk":
+		if *snapshotFile != "" || *walFile != "" {
+			log.Fatalln("Snapshots and the write-ahead log are only supported by the memory data store")
+		}
+
+		diskStore, err := openDiskStore(*dataDir)
+		if err != nil {
+			log.Fatalf("Failed to open the data store under %s: %v\n", *dataDir, err)
+		}
+		log.Printf("Loaded %d graphs from %s\n", len(diskStore.List()), *dataDir)
+		store = diskStore
+	default:
+		log.Fatalf("%s is not a valid data store", *storeType)
+	}
 
 	lis, err := net.Listen("tcp", addr)
 
@@ -22,10 +98,32 @@ func main() {
 	log.Printf("Listening on %s\n", addr)
 
 	s := grpc.NewServer()
-	pb.RegisterGraphServiceServer(s, &Server{})
+	pb.RegisterGraphServiceServer(s, &Server{store: store, streamWorkers: *streamWorkers, maxVertices: *maxVertices})
+
+	stopSnapshots := make(chan struct{})
+	if snapshots != nil {
+		go snapshots.run(*snapshotInterval, stopSnapshots)
+	}
+
+	// Stop serving on interrupt, letting the pending requests complete
+	go func() {
+		signals := make(chan os.Signal, 1)
+		signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+		<-signals
+
+		log.Println("Shutting down the server...")
+		s.GracefulStop()
+	}()
 
 	if err = s.Serve(lis); err != nil {
 		log.Fatalf("Failed to serve: %v\n", err)
 	}
 
+	if snapshots != nil {
+		close(stopSnapshots)
+		if err = snapshots.save(); err != nil {
+			log.Fatalf("Failed to save snapshot to %s: %v\n", *snapshotFile, err)
+		}
+		log.Printf("Saved snapshot to %s\n", *snapshotFile)
+	}
 }
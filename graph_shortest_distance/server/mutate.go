@@ -0,0 +1,225 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// AddEdges adds the edges in the request to the specified graph, keeping its ID unchanged.
+// The edges are validated the same way as in Post, against the current total number of vertices of the graph.
+// In a labeled graph, the labels of the new edges which do not exist yet are added as new vertices.
+func (s *Server) AddEdges(ctx context.Context, req *pb.AddEdgesRequest) (*pb.MutationResponse, error) {
+	log.Printf("AddEdges was invoked with: %v\n", req)
+
+	return s.mutate(req.Id, func(graph Graph) (Graph, error) {
+		newEdges, labels, err := indexGraphEdges(graph, req.Edges, true)
+		if err != nil {
+			return Graph{}, err
+		}
+
+		totalVertices := graph.totalVertices
+		if labels != nil {
+			if err = labels.validateSize(s.maxVertices); err != nil {
+				return Graph{}, err
+			}
+			totalVertices = int64(len(labels.labels))
+		}
+
+		if err = validateEdges(totalVertices, newEdges); err != nil {
+			return Graph{}, err
+		}
+
+		if graph.coordinates != nil && totalVertices != graph.totalVertices {
+			return Graph{}, status.Error(codes.InvalidArgument,
+				"New vertices cannot be added by their edges, since the vertices of the graph have coordinates")
+		}
+
+		edges := make([]*pb.Edge, 0, len(graph.edges)+len(newEdges))
+		edges = append(edges, graph.edges...)
+		edges = append(edges, newEdges...)
+
+		updated := newGraph(totalVertices, edges, graph.directed)
+		updated.labels = labels
+		updated.coordinates = graph.coordinates.withEdges(edges)
+		return updated, nil
+	})
+}
+
+// RemoveEdges removes from the specified graph every edge connecting the source node and destination node of one of
+// the edges in the request. In an undirected graph, the edges connecting the two nodes in the opposite direction are
+// removed as well. If any of the edges in the request does not match an edge of the graph, nothing is removed.
+func (s *Server) RemoveEdges(ctx context.Context, req *pb.RemoveEdgesRequest) (*pb.MutationResponse, error) {
+	log.Printf("RemoveEdges was invoked with: %v\n", req)
+
+	return s.mutate(req.Id, func(graph Graph) (Graph, error) {
+		targets, _, err := indexGraphEdges(graph, req.Edges, false)
+		if err != nil {
+			return Graph{}, err
+		}
+
+		if err = validateEdges(graph.totalVertices, targets); err != nil {
+			return Graph{}, err
+		}
+
+		// byNodes maps the nodes of every target edge to the indexes of the targets, so that the edges of the graph
+		// are scanned only once
+		byNodes := make(map[[2]int64][]int, len(targets))
+		for i, target := range targets {
+			key := edgeKey(target, graph.directed)
+			byNodes[key] = append(byNodes[key], i)
+		}
+
+		removed := make([]bool, len(graph.edges))
+		found := make([]bool, len(targets))
+		for i, edge := range graph.edges {
+			for _, t := range byNodes[edgeKey(edge, graph.directed)] {
+				if targets[t].Weight == nil || edgeWeight(edge) == *targets[t].Weight {
+					removed[i] = true
+					found[t] = true
+				}
+			}
+		}
+
+		for i, target := range targets {
+			if !found[i] {
+				return Graph{}, status.Errorf(
+					codes.NotFound,
+					fmt.Sprintf("The edge [%s -> %s] does not exist in the graph", graph.nodeName(target.Src),
+						graph.nodeName(target.Dest)),
+				)
+			}
+		}
+
+		edges := make([]*pb.Edge, 0, len(graph.edges))
+		for i, edge := range graph.edges {
+			if !removed[i] {
+				edges = append(edges, edge)
+			}
+		}
+
+		updated := newGraph(graph.totalVertices, edges, graph.directed)
+		updated.labels = graph.labels
+		updated.coordinates = graph.coordinates.withEdges(edges)
+		return updated, nil
+	})
+}
+
+// AddVertices appends isolated vertices to the specified graph. The new vertices are numbered from the previous total
+// number of vertices on, so the existing vertices and edges are unaffected. The new vertices of a labeled graph are
+// given by their labels. The coordinates of the new vertices must be given if, and only if, the vertices of the graph
+// have coordinates.
+func (s *Server) AddVertices(ctx context.Context, req *pb.AddVerticesRequest) (*pb.MutationResponse, error) {
+	log.Printf("AddVertices was invoked with: %v\n", req)
+
+	return s.mutate(req.Id, func(graph Graph) (Graph, error) {
+		if req.Count < 0 {
+			return Graph{}, status.Errorf(
+				codes.InvalidArgument,
+				fmt.Sprintf("Invalid number of vertices to add: %d. Must not be negative.", req.Count),
+			)
+		}
+		if req.Count > s.maxVertices-graph.totalVertices {
+			return Graph{}, status.Errorf(
+				codes.InvalidArgument,
+				fmt.Sprintf("Cannot add %d vertices to a graph of %d vertices, the total number of vertices "+
+					"would exceed %d", req.Count, graph.totalVertices, s.maxVertices),
+			)
+		}
+
+		if graph.labels != nil {
+			if req.Count != 0 && int(req.Count) != len(req.Labels) {
+				return Graph{}, status.Errorf(
+					codes.InvalidArgument,
+					fmt.Sprintf("The number of vertices to add (%d) differs from the number of labels (%d)",
+						req.Count, len(req.Labels)),
+				)
+			}
+			// The count may be left to 0, in which case only the labels give the number of vertices to add
+			if int64(len(req.Labels)) > s.maxVertices-graph.totalVertices {
+				return Graph{}, status.Errorf(
+					codes.InvalidArgument,
+					fmt.Sprintf("Cannot add %d vertices to a graph of %d vertices, the total number of vertices "+
+						"would exceed %d", len(req.Labels), graph.totalVertices, s.maxVertices),
+				)
+			}
+
+			labels := graph.labels.clone()
+			if err := labels.addVertices(req.Labels); err != nil {
+				return Graph{}, err
+			}
+
+			coordinates, err := graph.coordinates.addCoordinates(int64(len(labels.labels))-graph.totalVertices,
+				req.Coordinates, graph.edges)
+			if err != nil {
+				return Graph{}, err
+			}
+
+			updated := newGraph(int64(len(labels.labels)), graph.edges, graph.directed)
+			updated.labels = labels
+			updated.coordinates = coordinates
+			return updated, nil
+		}
+
+		if len(req.Labels) > 0 {
+			return Graph{}, status.Error(codes.InvalidArgument,
+				"The vertices are given by labels, but the graph is not labeled")
+		}
+
+		coordinates, err := graph.coordinates.addCoordinates(req.Count, req.Coordinates, graph.edges)
+		if err != nil {
+			return Graph{}, err
+		}
+
+		updated := newGraph(graph.totalVertices+req.Count, graph.edges, graph.directed)
+		updated.coordinates = coordinates
+		return updated, nil
+	})
+}
+
+// mutate replaces the graph associated with the specified ID by the graph returned from the update function, which
+// receives the current graph. The creation time of the graph is kept. The mutations and deletions are serialized so
+// that none of them is lost, while the queries keep reading the previous graph until the new one is stored.
+func (s *Server) mutate(id int64, update func(Graph) (Graph, error)) (*pb.MutationResponse, error) {
+	s.mutationMu.Lock()
+	defer s.mutationMu.Unlock()
+
+	graph, ok := s.store.Get(id)
+
+	// The graph does not exist in the data store
+	if !ok {
+		return nil, status.Errorf(
+			codes.NotFound,
+			fmt.Sprintf("The graph[id=%d] does not exist in the data store", id),
+		)
+	}
+
+	updated, err := update(graph)
+	if err != nil {
+		return nil, err
+	}
+	updated.createdAt = graph.createdAt
+	updated.version = graph.version + 1
+
+	if err = s.store.Put(id, updated); err != nil {
+		return nil, status.Errorf(
+			codes.Internal,
+			fmt.Sprintf("Failed to save the graph[id=%d]: %v", id, err),
+		)
+	}
+
+	return &pb.MutationResponse{TotalVertices: updated.totalVertices, TotalEdges: int64(len(updated.edges))}, nil
+}
+
+// edgeKey returns the source node and destination node of the edge, in ascending order if the graph is undirected, so
+// that the edges connecting the same two nodes in either direction share the same key.
+func edgeKey(edge *pb.Edge, directed bool) [2]int64 {
+	if !directed && edge.Src > edge.Dest {
+		return [2]int64{edge.Dest, edge.Src}
+	}
+	return [2]int64{edge.Src, edge.Dest}
+}
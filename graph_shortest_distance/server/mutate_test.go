@@ -0,0 +1,330 @@
+package main
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"math"
+	"testing"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// TestServer_Mutation tests for modifying a graph in place and querying it after each mutation
+func TestServer_Mutation(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	res, err := client.Post(context.Background(), &pb.PostRequest{
+		TotalVertices: 3,
+		Edges: []*pb.Edge{
+			{Src: 0, Dest: 1},
+			{Src: 1, Dest: 2},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Post got unexpected error")
+	}
+
+	id := res.Result
+
+	tests := []struct {
+		mutate        func() (*pb.MutationResponse, error)
+		totalVertices int64
+		totalEdges    int64
+		src           int64
+		dest          int64
+		expected      int64
+		unreachable   bool
+	}{
+		{
+			mutate: func() (*pb.MutationResponse, error) {
+				return client.AddEdges(context.Background(), &pb.AddEdgesRequest{
+					Id:    id,
+					Edges: []*pb.Edge{{Src: 2, Dest: 0}},
+				})
+			},
+			totalVertices: 3,
+			totalEdges:    3,
+			src:           0,
+			dest:          2,
+			expected:      1,
+		},
+		{
+			// The edges are matched in either direction, since the graph is undirected
+			mutate: func() (*pb.MutationResponse, error) {
+				return client.RemoveEdges(context.Background(), &pb.RemoveEdgesRequest{
+					Id:    id,
+					Edges: []*pb.Edge{{Src: 0, Dest: 2}, {Src: 1, Dest: 2}},
+				})
+			},
+			totalVertices: 3,
+			totalEdges:    1,
+			src:           0,
+			dest:          2,
+			unreachable:   true,
+		},
+		{
+			mutate: func() (*pb.MutationResponse, error) {
+				return client.AddVertices(context.Background(), &pb.AddVerticesRequest{Id: id, Count: 2})
+			},
+			totalVertices: 5,
+			totalEdges:    1,
+			src:           1,
+			dest:          4,
+			unreachable:   true,
+		},
+		{
+			mutate: func() (*pb.MutationResponse, error) {
+				return client.AddEdges(context.Background(), &pb.AddEdgesRequest{
+					Id: id,
+					Edges: []*pb.Edge{
+						{Src: 1, Dest: 4, Weight: proto.Int32(5)},
+						{Src: 1, Dest: 3, Weight: proto.Int32(1)},
+						{Src: 3, Dest: 4, Weight: proto.Int32(2)},
+					},
+				})
+			},
+			totalVertices: 5,
+			totalEdges:    4,
+			src:           0,
+			dest:          4,
+			expected:      4,
+		},
+		{
+			// Only the edges having the given weight are removed
+			mutate: func() (*pb.MutationResponse, error) {
+				return client.RemoveEdges(context.Background(), &pb.RemoveEdgesRequest{
+					Id:    id,
+					Edges: []*pb.Edge{{Src: 4, Dest: 3, Weight: proto.Int32(2)}},
+				})
+			},
+			totalVertices: 5,
+			totalEdges:    3,
+			src:           0,
+			dest:          4,
+			expected:      6,
+		},
+	}
+
+	for i, tt := range tests {
+		mutationRes, err := tt.mutate()
+
+		if err != nil {
+			t.Fatalf("Mutation #%d got unexpected error: %v", i, err)
+		}
+
+		if mutationRes.TotalVertices != tt.totalVertices || mutationRes.TotalEdges != tt.totalEdges {
+			t.Errorf("Mutation #%d = %v, expected: %d vertices and %d edges", i, mutationRes, tt.totalVertices,
+				tt.totalEdges)
+		}
+
+		distRes, err := client.Dist(context.Background(), &pb.DistRequest{Id: id, Src: tt.src, Dest: tt.dest})
+
+		if err != nil {
+			t.Fatalf("Dist after mutation #%d got unexpected error: %v", i, err)
+		}
+
+		if distRes.Result != tt.expected || distRes.Reachable == tt.unreachable {
+			t.Errorf("Dist after mutation #%d = %v, expected: %v", i, distRes, tt.expected)
+		}
+	}
+}
+
+// TestServer_MutationInvalidInput tests for invalid mutations, which must leave the graph unchanged
+func TestServer_MutationInvalidInput(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	_, err = client.Post(context.Background(), &pb.PostRequest{
+		TotalVertices: 3,
+		Edges: []*pb.Edge{
+			{Src: 0, Dest: 1},
+		},
+		Directed: true,
+	})
+
+	if err != nil {
+		t.Fatalf("Post got unexpected error")
+	}
+
+	tests := []struct {
+		mutate func() (*pb.MutationResponse, error)
+		code   codes.Code
+	}{
+		{
+			// The graph does not exist
+			mutate: func() (*pb.MutationResponse, error) {
+				return client.AddEdges(context.Background(), &pb.AddEdgesRequest{
+					Id:    1,
+					Edges: []*pb.Edge{{Src: 0, Dest: 1}},
+				})
+			},
+			code: codes.NotFound,
+		},
+		{
+			// The edge node does not exist
+			mutate: func() (*pb.MutationResponse, error) {
+				return client.AddEdges(context.Background(), &pb.AddEdgesRequest{
+					Id:    0,
+					Edges: []*pb.Edge{{Src: 1, Dest: 2}, {Src: 2, Dest: 3}},
+				})
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			// Negative weight
+			mutate: func() (*pb.MutationResponse, error) {
+				return client.AddEdges(context.Background(), &pb.AddEdgesRequest{
+					Id:    0,
+					Edges: []*pb.Edge{{Src: 1, Dest: 2, Weight: proto.Int32(-1)}},
+				})
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			// The edge is only matched in its own direction, since the graph is directed
+			mutate: func() (*pb.MutationResponse, error) {
+				return client.RemoveEdges(context.Background(), &pb.RemoveEdgesRequest{
+					Id:    0,
+					Edges: []*pb.Edge{{Src: 1, Dest: 0}},
+				})
+			},
+			code: codes.NotFound,
+		},
+		{
+			// The weight does not match
+			mutate: func() (*pb.MutationResponse, error) {
+				return client.RemoveEdges(context.Background(), &pb.RemoveEdgesRequest{
+					Id:    0,
+					Edges: []*pb.Edge{{Src: 0, Dest: 1}, {Src: 0, Dest: 1, Weight: proto.Int32(2)}},
+				})
+			},
+			code: codes.NotFound,
+		},
+		{
+			// Count < 0
+			mutate: func() (*pb.MutationResponse, error) {
+				return client.AddVertices(context.Background(), &pb.AddVerticesRequest{Id: 0, Count: -1})
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			// The total number of vertices overflows
+			mutate: func() (*pb.MutationResponse, error) {
+				return client.AddVertices(context.Background(), &pb.AddVerticesRequest{Id: 0, Count: math.MaxInt64})
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			// The total number of vertices reaches math.MaxInt64
+			mutate: func() (*pb.MutationResponse, error) {
+				return client.AddVertices(context.Background(), &pb.AddVerticesRequest{Id: 0, Count: math.MaxInt64 - 3})
+			},
+			code: codes.InvalidArgument,
+		},
+		{
+			// The total number of vertices exceeds the maximum by one
+			mutate: func() (*pb.MutationResponse, error) {
+				return client.AddVertices(context.Background(), &pb.AddVerticesRequest{
+					Id:    0,
+					Count: testServer.maxVertices - 2,
+				})
+			},
+			code: codes.InvalidArgument,
+		},
+	}
+
+	for i, tt := range tests {
+		res, err := tt.mutate()
+
+		if err == nil {
+			t.Fatal("Failed to catch expected error\n")
+		}
+
+		if status.Code(err) != tt.code {
+			t.Errorf("Mutation #%d = %v, %v, expected code: %v", i, res, err, tt.code)
+		}
+	}
+
+	graph, _ := testServer.store.Get(0)
+	if graph.totalVertices != 3 || len(graph.edges) != 1 {
+		t.Errorf("Graph after invalid mutations has %d vertices and %d edges, expected: 3 vertices and 1 edge",
+			graph.totalVertices, len(graph.edges))
+	}
+}
+
+// TestServer_RemoveEdgesLargeBatch tests for removing many edges at once from a large graph, the targets being given
+// in the opposite direction of the edges of the undirected graph
+func TestServer_RemoveEdgesLargeBatch(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	const totalVertices = 10000
+	edges := randomEdges(totalVertices, 200000, 1)
+	testServer.store.Put(0, newGraph(totalVertices, edges, false))
+
+	targets := make([]*pb.Edge, 0, len(edges)/10)
+	removed := make(map[[2]int64]bool)
+	for i := 0; i < len(edges); i += 10 {
+		targets = append(targets, &pb.Edge{Src: edges[i].Dest, Dest: edges[i].Src})
+		removed[edgeKey(edges[i], false)] = true
+	}
+
+	var expected []*pb.Edge
+	for _, edge := range edges {
+		if !removed[edgeKey(edge, false)] {
+			expected = append(expected, edge)
+		}
+	}
+
+	res, err := client.RemoveEdges(context.Background(), &pb.RemoveEdgesRequest{Id: 0, Edges: targets})
+	if err != nil {
+		t.Fatalf("RemoveEdges got unexpected error: %v", err)
+	}
+
+	if res.TotalEdges != int64(len(expected)) {
+		t.Fatalf("RemoveEdges = %v, expected: %d edges", res, len(expected))
+	}
+
+	graph, _ := testServer.store.Get(0)
+	for i := range expected {
+		if !proto.Equal(graph.edges[i], expected[i]) {
+			t.Fatalf("RemoveEdges kept edge #%d = %v, expected: %v", i, graph.edges[i], expected[i])
+		}
+	}
+}
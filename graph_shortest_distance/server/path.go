@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"log"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// Path computes one shortest path between the source node and destination node in the graph specified in the
+// request, and returns its length together with the ordered vertices and edges along the path.
+// The validation and the computation algorithm are the same as the unary Dist service.
+func (s *Server) Path(ctx context.Context, req *pb.DistRequest) (*pb.PathResponse, error) {
+	log.Printf("Path was invoked with: %v\n", req)
+
+	graph, err := s.queryGraph(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	src, err := queryNode(graph, req.Src, req.SrcLabel, "source")
+	if err != nil {
+		return nil, err
+	}
+
+	dest, err := queryNode(graph, req.Dest, req.DestLabel, "destination")
+	if err != nil {
+		return nil, err
+	}
+
+	shortestDistance, reachable, parent, err := computeShortestDistance(ctx, graph, src, dest)
+	if err != nil {
+		return nil, err
+	}
+
+	if !reachable {
+		return &pb.PathResponse{}, nil
+	}
+
+	vertices := buildPath(parent, src, dest)
+	res := &pb.PathResponse{
+		Result:    shortestDistance,
+		Vertices:  vertices,
+		Edges:     buildPathEdges(graph, vertices),
+		Reachable: true,
+	}
+
+	// The path of a labeled graph is also given by the labels of its vertices
+	if graph.labels != nil {
+		res.VertexLabels = make([]string, len(vertices))
+		for i, vertex := range vertices {
+			res.VertexLabels[i] = graph.labels.labels[vertex]
+		}
+		graph.labels.labelEdges(res.Edges)
+	}
+
+	return res, nil
+}
+
+// buildPath reconstructs the ordered vertices of the path from the source node to the destination node,
+// by following the parent pointers backwards from the destination node
+func buildPath(parent []int64, src int64, dest int64) []int64 {
+	var path []int64
+	for node := dest; node != src; node = parent[node] {
+		path = append(path, node)
+	}
+	path = append(path, src)
+
+	// Reverse the path, so it starts at the source node
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path
+}
+
+// buildPathEdges returns the edges traversed by the path going through the given vertices. When several edges
+// connect the same pair of vertices, the one with the lowest weight is the one taken by the path.
+func buildPathEdges(graph Graph, vertices []int64) []*pb.Edge {
+	pathEdges := make([]*pb.Edge, len(vertices)-1)
+	for i := range pathEdges {
+		src := vertices[i]
+		dest := vertices[i+1]
+		pathEdges[i] = &pb.Edge{Src: src, Dest: dest}
+
+		if !graph.weighted {
+			continue
+		}
+
+		weights := graph.adj.neighbourWeights(src)
+		for j, neighbour := range graph.adj.neighbours(src) {
+			if neighbour == dest && (pathEdges[i].Weight == nil || weights[j] < *pathEdges[i].Weight) {
+				weight := weights[j]
+				pathEdges[i].Weight = &weight
+			}
+		}
+	}
+
+	return pathEdges
+}
@@ -0,0 +1,207 @@
+package main
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+	"reflect"
+	"testing"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// TestServer_Path tests for computing one shortest path
+func TestServer_Path(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	graphs := []struct {
+		totalVertices int64
+		edgesPb       []*pb.Edge
+		directed      bool
+	}{
+		{
+			totalVertices: 8,
+			edgesPb: []*pb.Edge{
+				{Src: 0, Dest: 1},
+				{Src: 0, Dest: 3},
+				{Src: 1, Dest: 2},
+				{Src: 3, Dest: 4},
+				{Src: 3, Dest: 7},
+				{Src: 4, Dest: 5},
+				{Src: 4, Dest: 6},
+				{Src: 4, Dest: 7},
+				{Src: 5, Dest: 6},
+				{Src: 6, Dest: 7},
+			},
+		},
+		{
+			totalVertices: 4,
+			edgesPb: []*pb.Edge{
+				{Src: 0, Dest: 1, Weight: proto.Int32(4)},
+				{Src: 0, Dest: 2, Weight: proto.Int32(1)},
+				{Src: 1, Dest: 2, Weight: proto.Int32(2)},
+				{Src: 2, Dest: 1, Weight: proto.Int32(1)},
+				{Src: 1, Dest: 3, Weight: proto.Int32(1)},
+			},
+			directed: true,
+		},
+		{
+			totalVertices: 3,
+			edgesPb: []*pb.Edge{
+				{Src: 0, Dest: 1},
+			},
+		},
+	}
+
+	for _, graph := range graphs {
+		_, err := client.Post(context.Background(), &pb.PostRequest{
+			TotalVertices: graph.totalVertices,
+			Edges:         graph.edgesPb,
+			Directed:      graph.directed,
+		})
+
+		if err != nil {
+			t.Errorf("Post(%+v) got unexpected error", graph)
+		}
+	}
+
+	tests := []struct {
+		expected    int64
+		unreachable bool
+		vertices    []int64
+		edgesPb     []*pb.Edge
+		id          int64
+		src         int64
+		dest        int64
+	}{
+		{
+			expected: 5,
+			vertices: []int64{2, 1, 0, 3, 4, 6},
+			edgesPb: []*pb.Edge{
+				{Src: 2, Dest: 1},
+				{Src: 1, Dest: 0},
+				{Src: 0, Dest: 3},
+				{Src: 3, Dest: 4},
+				{Src: 4, Dest: 6},
+			},
+			id:   0,
+			src:  2,
+			dest: 6,
+		},
+		{
+			expected: 0,
+			vertices: []int64{1},
+			id:       0,
+			src:      1,
+			dest:     1,
+		},
+		{
+			expected: 3,
+			vertices: []int64{0, 2, 1, 3},
+			edgesPb: []*pb.Edge{
+				{Src: 0, Dest: 2, Weight: proto.Int32(1)},
+				{Src: 2, Dest: 1, Weight: proto.Int32(1)},
+				{Src: 1, Dest: 3, Weight: proto.Int32(1)},
+			},
+			id:   1,
+			src:  0,
+			dest: 3,
+		},
+		{
+			unreachable: true,
+			id:          2,
+			src:         1,
+			dest:        2,
+		},
+	}
+
+	for _, tt := range tests {
+		res, err := client.Path(context.Background(), &pb.DistRequest{
+			Id:   tt.id,
+			Src:  tt.src,
+			Dest: tt.dest,
+		})
+
+		if err != nil {
+			t.Fatalf("Path(%+v) got unexpected error", tt)
+		}
+
+		if res.Result != tt.expected || res.Reachable == tt.unreachable {
+			t.Errorf("Path(%+v) = %v, expected: %v", tt, res.Result, tt.expected)
+		}
+
+		if !reflect.DeepEqual(res.Vertices, tt.vertices) {
+			t.Errorf("Path(%+v) vertices = %v, expected: %v", tt, res.Vertices, tt.vertices)
+		}
+
+		if len(res.Edges) != len(tt.edgesPb) {
+			t.Fatalf("Path(%+v) edges = %v, expected: %v", tt, res.Edges, tt.edgesPb)
+		}
+		for i := range tt.edgesPb {
+			if !proto.Equal(res.Edges[i], tt.edgesPb[i]) {
+				t.Errorf("Path(%+v) edges = %v, expected: %v", tt, res.Edges, tt.edgesPb)
+			}
+		}
+	}
+}
+
+// TestServer_PathInvalidInput tests for invalid parameters
+func TestServer_PathInvalidInput(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	_, err = client.Post(context.Background(), &pb.PostRequest{
+		TotalVertices: 3,
+		Edges: []*pb.Edge{
+			{Src: 0, Dest: 1},
+		},
+	})
+
+	if err != nil {
+		t.Fatalf("Post got unexpected error")
+	}
+
+	reqs := []*pb.DistRequest{
+		// The queried graph does not exist
+		{Id: 1, Src: 0, Dest: 0},
+		// Source node does not exist
+		{Id: 0, Src: 3, Dest: 0},
+		// Destination node does not exist
+		{Id: 0, Src: 0, Dest: 3},
+		// Src < 0
+		{Id: 0, Src: -1, Dest: 0},
+		// Dest < 0
+		{Id: 0, Src: 0, Dest: -1},
+	}
+
+	for _, req := range reqs {
+		res, err := client.Path(context.Background(), req)
+		if err == nil {
+			t.Fatal("Failed to catch expected error\n")
+		} else if res != nil {
+			t.Fatalf("Path(%+v) = %v, expected: nil", req, res.Result)
+		}
+	}
+}
@@ -6,63 +6,150 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"log"
+	"time"
 
 	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
 )
 
-// Post posts a new graph representation to the server's data store.
+// Post posts a new graph representation to the server's data store. The vertices are either given by their indexes,
+// from 0 to the total number of vertices - 1, or by string labels, in which case the graph is labeled.
+// The coordinates of the vertices can optionally be given, in the order of their indexes, so that the shortest
+// distances are computed by A* search.
 // Returns the new graph's unique ID for future reference.
-func (*Server) Post(ctx context.Context, req *pb.PostRequest) (*pb.PostResponse, error) {
+func (s *Server) Post(ctx context.Context, req *pb.PostRequest) (*pb.PostResponse, error) {
 	log.Printf("Post was invoked with: %v\n", req)
 
 	totalVertices := req.TotalVertices
 	edges := req.Edges
 
+	// The vertices of a labeled graph are given by its labels
+	if len(req.Labels) > 0 || hasLabels(edges) {
+		if err := validateLabeledTotalVertices(totalVertices); err != nil {
+			return nil, err
+		}
+
+		graph, err := newLabeledGraph(req.Labels, edges, req.Directed, s.maxVertices)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := attachCoordinates(&graph, req.Coordinates, req.Heuristic); err != nil {
+			return nil, err
+		}
+
+		currId, err := s.saveNewGraph(graph)
+		if err != nil {
+			return nil, err
+		}
+
+		return &pb.PostResponse{Result: currId}, nil
+	}
+
 	// Parameter validation
+	if err := validateTotalVertices(totalVertices, s.maxVertices); err != nil {
+		return nil, err
+	}
+
+	if err := validateEdges(totalVertices, edges); err != nil {
+		return nil, err
+	}
+
+	graph := newGraph(totalVertices, edges, req.Directed)
+	if err := attachCoordinates(&graph, req.Coordinates, req.Heuristic); err != nil {
+		return nil, err
+	}
+
+	// Saving the graph
+	currId, err := s.saveNewGraph(graph)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.PostResponse{Result: currId}, nil
+}
+
+// defaultMaxVertices is the default maximum total number of vertices of a graph created by a client. The adjacency
+// structure of a graph and every query on it allocate memory proportional to its total number of vertices, so the
+// number given by a client must be bounded before anything is allocated.
+const defaultMaxVertices = 1 << 26
+
+// saveNewGraph saves an already validated graph under a newly allocated ID, which is returned
+func (s *Server) saveNewGraph(graph Graph) (int64, error) {
+	currId, err := s.store.NextID()
+	if err != nil {
+		return 0, status.Errorf(
+			codes.Internal,
+			fmt.Sprintf("Failed to allocate a new graph ID: %v", err),
+		)
+	}
+
+	graph.createdAt = time.Now()
+
+	if err = s.store.Put(currId, graph); err != nil {
+		return 0, status.Errorf(
+			codes.Internal,
+			fmt.Sprintf("Failed to save the graph[id=%d]: %v", currId, err),
+		)
+	}
+
+	return currId, nil
+}
+
+// validateTotalVertices checks the total number of vertices of a new graph against the maximum of the server
+func validateTotalVertices(totalVertices int64, maxVertices int64) error {
 	if totalVertices < 0 {
-		return nil, status.Errorf(
+		return status.Errorf(
 			codes.InvalidArgument,
 			fmt.Sprintf("Invalid total number of vertices: %d. Must not be negative.", totalVertices),
 		)
 	}
+	if totalVertices > maxVertices {
+		return status.Errorf(
+			codes.InvalidArgument,
+			fmt.Sprintf("Invalid total number of vertices: %d. Must not exceed %d.", totalVertices, maxVertices),
+		)
+	}
+
+	return nil
+}
 
+// validateEdges checks that every edge connects two existing nodes of a graph having the given total number of
+// vertices, and that no edge weight is negative
+func validateEdges(totalVertices int64, edges []*pb.Edge) error {
 	for _, edge := range edges {
 		if edge.Src < 0 {
-			return nil, status.Errorf(
+			return status.Errorf(
 				codes.InvalidArgument,
 				fmt.Sprintf("Invalid edge node: %d. Must not be negative.", edge.Src),
 			)
 		}
 		if edge.Dest < 0 {
-			return nil, status.Errorf(
+			return status.Errorf(
 				codes.InvalidArgument,
 				fmt.Sprintf("Invalid edge node: %d. Must not be negative.", edge.Dest),
 			)
 		}
 		if edge.Src >= totalVertices {
-			return nil, status.Errorf(
+			return status.Errorf(
 				codes.InvalidArgument,
 				fmt.Sprintf("The node value [%d] is greater than or equal to the total number of nodes, "+
 					"meaning the node does not exist in the graph", edge.Src),
 			)
 		}
 		if edge.Dest >= totalVertices {
-			return nil, status.Errorf(
+			return status.Errorf(
 				codes.InvalidArgument,
 				fmt.Sprintf("The node value [%d] is greater than or equal to the total number of nodes, "+
 					"meaning the node does not exist in the graph", edge.Dest),
 			)
 		}
+		if edgeWeight(edge) < 0 {
+			return status.Errorf(
+				codes.InvalidArgument,
+				fmt.Sprintf("Invalid edge weight: %d. Must not be negative.", edgeWeight(edge)),
+			)
+		}
 	}
 
-	// Saving the graph
-	newGraph := Graph{
-		totalVertices: totalVertices,
-		edges:         edges,
-	}
-	currId := idHead
-	graphStore[idHead] = newGraph
-	idHead++
-
-	return &pb.PostResponse{Result: currId}, nil
+	return nil
 }
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"io"
+	"log"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// PostStream posts a new graph uploaded over a stream, for graphs too large to fit in a single PostRequest.
+// The stream starts with a header giving the total number of vertices, followed by chunks of edges which are validated
+// as soon as they are received, the same way as in Post. The vertices of a labeled graph are given by labels, and the
+// chunks may also add vertices by their labels. The coordinates of the vertices may be spread over the chunks, in index
+// order, and are attached once the whole graph is received. The graph is only saved once the client closes the stream,
+// so a failed or interrupted upload leaves nothing behind.
+// Returns the new graph's unique ID for future reference.
+func (s *Server) PostStream(stream pb.GraphService_PostStreamServer) error {
+	log.Println("PostStream was invoked")
+
+	var header *pb.PostStreamHeader
+	var edges []*pb.Edge
+	// labels is the dictionary of a labeled graph, which grows with every chunk
+	var labels *labelDictionary
+	var coordinates []*pb.Point
+
+	for {
+		req, err := stream.Recv()
+
+		if err == io.EOF {
+			break
+		}
+
+		if err != nil {
+			log.Printf("Error while reading client stream: %v\n", err)
+			return status.Errorf(
+				status.Code(err),
+				fmt.Sprintf("Error while reading client stream: %v", err),
+			)
+		}
+
+		switch data := req.Data.(type) {
+		case *pb.PostStreamRequest_Header:
+			if header != nil {
+				return status.Error(codes.InvalidArgument,
+					"The header must only be sent once, at the start of the stream")
+			}
+
+			if data.Header.Labeled {
+				err = validateLabeledTotalVertices(data.Header.TotalVertices)
+				labels = &labelDictionary{indexes: make(map[string]int64)}
+			} else {
+				err = validateTotalVertices(data.Header.TotalVertices, s.maxVertices)
+			}
+			if err != nil {
+				return err
+			}
+
+			header = data.Header
+		case *pb.PostStreamRequest_Edges:
+			if header == nil {
+				return status.Error(codes.InvalidArgument, "The header must be sent before the edges")
+			}
+
+			chunk := data.Edges.Edges
+			totalVertices := header.TotalVertices
+			if labels != nil {
+				if err = labels.addVertices(data.Edges.Labels); err != nil {
+					return err
+				}
+				if chunk, err = labels.indexEdges(chunk, true); err != nil {
+					return err
+				}
+				if err = labels.validateSize(s.maxVertices); err != nil {
+					return err
+				}
+				totalVertices = int64(len(labels.labels))
+			} else if len(data.Edges.Labels) > 0 || hasLabels(chunk) {
+				return status.Error(codes.InvalidArgument,
+					"The vertices are given by labels, but the header does not declare the graph as labeled")
+			}
+
+			if err = validateEdges(totalVertices, chunk); err != nil {
+				return err
+			}
+
+			edges = append(edges, chunk...)
+
+			coordinates = append(coordinates, data.Edges.Coordinates...)
+			maxCoordinates := header.TotalVertices
+			if labels != nil {
+				maxCoordinates = s.maxVertices
+			}
+			if int64(len(coordinates)) > maxCoordinates {
+				return status.Errorf(
+					codes.InvalidArgument,
+					fmt.Sprintf("The number of coordinates (%d) exceeds the total number of vertices (%d)",
+						len(coordinates), maxCoordinates),
+				)
+			}
+		default:
+			return status.Error(codes.InvalidArgument, "The message carries neither a header nor edges")
+		}
+	}
+
+	if header == nil {
+		return status.Error(codes.InvalidArgument, "The stream was closed before the header was sent")
+	}
+
+	totalVertices := header.TotalVertices
+	if labels != nil {
+		totalVertices = int64(len(labels.labels))
+	}
+
+	graph := newGraph(totalVertices, edges, header.Directed)
+	graph.labels = labels
+	if err := attachCoordinates(&graph, coordinates, header.Heuristic); err != nil {
+		return err
+	}
+
+	// Saving the graph
+	currId, err := s.saveNewGraph(graph)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("PostStream received %d edges for the graph[id=%d]\n", len(edges), currId)
+
+	return stream.SendAndClose(&pb.PostResponse{Result: currId})
+}
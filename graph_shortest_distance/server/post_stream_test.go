@@ -0,0 +1,239 @@
+package main
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"math"
+	"reflect"
+	"testing"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// TestServer_PostStream tests for uploading a graph exceeding the default maximum message size of gRPC
+func TestServer_PostStream(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	const totalVertices = 100000
+	edges := randomEdges(totalVertices, 500000, 1)
+	for i, edge := range edges {
+		edge.Weight = proto.Int32(int32(i % 100))
+	}
+
+	// The graph is too large for the unary Post
+	_, err = client.Post(context.Background(), &pb.PostRequest{TotalVertices: totalVertices, Edges: edges})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Fatalf("Post got %v, expected code: %v", err, codes.ResourceExhausted)
+	}
+
+	stream, err := client.PostStream(context.Background())
+	if err != nil {
+		t.Fatalf("PostStream got unexpected error: %v", err)
+	}
+
+	err = stream.Send(&pb.PostStreamRequest{Data: &pb.PostStreamRequest_Header{
+		Header: &pb.PostStreamHeader{TotalVertices: totalVertices, Directed: true},
+	}})
+	if err != nil {
+		t.Fatalf("Send got unexpected error: %v", err)
+	}
+
+	for start := 0; start < len(edges); start += 65536 {
+		end := start + 65536
+		if end > len(edges) {
+			end = len(edges)
+		}
+
+		err = stream.Send(&pb.PostStreamRequest{Data: &pb.PostStreamRequest_Edges{
+			Edges: &pb.EdgeChunk{Edges: edges[start:end]},
+		}})
+		if err != nil {
+			t.Fatalf("Send got unexpected error: %v", err)
+		}
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("PostStream got unexpected error: %v", err)
+	}
+
+	graph, ok := testServer.store.Get(res.Result)
+	if !ok {
+		t.Fatalf("The graph[id=%d] does not exist in the data store", res.Result)
+	}
+
+	if graph.totalVertices != totalVertices || !graph.directed || !graph.weighted || len(graph.edges) != len(edges) {
+		t.Fatalf("PostStream saved %d vertices and %d edges, directed: %v, weighted: %v, expected: %d vertices and "+
+			"%d edges, directed and weighted", graph.totalVertices, len(graph.edges), graph.directed, graph.weighted,
+			totalVertices, len(edges))
+	}
+	for i := range edges {
+		if !proto.Equal(graph.edges[i], edges[i]) {
+			t.Fatalf("PostStream saved edge #%d = %v, expected: %v", i, graph.edges[i], edges[i])
+		}
+	}
+}
+
+// TestServer_PostStreamCoordinates tests for uploading a graph whose vertex coordinates are spread over the chunks,
+// one of which carries coordinates only
+func TestServer_PostStreamCoordinates(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	stream, err := client.PostStream(context.Background())
+	if err != nil {
+		t.Fatalf("PostStream got unexpected error: %v", err)
+	}
+
+	// A square whose sides cost 1
+	upload := []*pb.PostStreamRequest{
+		{Data: &pb.PostStreamRequest_Header{Header: &pb.PostStreamHeader{
+			TotalVertices: 4,
+			Heuristic:     pb.Heuristic_HEURISTIC_MANHATTAN,
+		}}},
+		{Data: &pb.PostStreamRequest_Edges{Edges: &pb.EdgeChunk{
+			Edges:       []*pb.Edge{{Src: 0, Dest: 1}, {Src: 1, Dest: 2}},
+			Coordinates: []*pb.Point{{X: 0, Y: 0}, {X: 1, Y: 0}},
+		}}},
+		{Data: &pb.PostStreamRequest_Edges{Edges: &pb.EdgeChunk{
+			Edges: []*pb.Edge{{Src: 2, Dest: 3}, {Src: 3, Dest: 0}},
+		}}},
+		{Data: &pb.PostStreamRequest_Edges{Edges: &pb.EdgeChunk{
+			Coordinates: []*pb.Point{{X: 1, Y: 1}, {X: 0, Y: 1}},
+		}}},
+	}
+	for _, req := range upload {
+		if err = stream.Send(req); err != nil {
+			t.Fatalf("Send got unexpected error: %v", err)
+		}
+	}
+
+	res, err := stream.CloseAndRecv()
+	if err != nil {
+		t.Fatalf("PostStream got unexpected error: %v", err)
+	}
+
+	graph, _ := testServer.store.Get(res.Result)
+	if graph.coordinates == nil || graph.coordinates.heuristic != pb.Heuristic_HEURISTIC_MANHATTAN {
+		t.Fatalf("PostStream saved coordinates %+v, expected: 4 points with the manhattan heuristic",
+			graph.coordinates)
+	}
+	expected := []point{{0, 0}, {1, 0}, {1, 1}, {0, 1}}
+	if !reflect.DeepEqual(graph.coordinates.points, expected) {
+		t.Errorf("PostStream saved points %v, expected: %v", graph.coordinates.points, expected)
+	}
+
+	distRes, err := client.Dist(context.Background(), &pb.DistRequest{Id: res.Result, Src: 0, Dest: 2})
+	if err != nil {
+		t.Fatalf("Dist got unexpected error: %v", err)
+	}
+	if distRes.Result != 2 || !distRes.Reachable {
+		t.Errorf("Dist = %v, expected: %d", distRes, 2)
+	}
+}
+
+// TestServer_PostStreamInvalidInput tests for invalid uploads, which must not save anything
+func TestServer_PostStreamInvalidInput(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	header := func(totalVertices int64) *pb.PostStreamRequest {
+		return &pb.PostStreamRequest{Data: &pb.PostStreamRequest_Header{
+			Header: &pb.PostStreamHeader{TotalVertices: totalVertices},
+		}}
+	}
+	chunk := func(edges ...*pb.Edge) *pb.PostStreamRequest {
+		return &pb.PostStreamRequest{Data: &pb.PostStreamRequest_Edges{Edges: &pb.EdgeChunk{Edges: edges}}}
+	}
+	points := func(points ...*pb.Point) *pb.PostStreamRequest {
+		return &pb.PostStreamRequest{Data: &pb.PostStreamRequest_Edges{Edges: &pb.EdgeChunk{Coordinates: points}}}
+	}
+
+	uploads := [][]*pb.PostStreamRequest{
+		// The header is missing
+		{},
+		{chunk(&pb.Edge{Src: 0, Dest: 1})},
+		// The header is sent twice
+		{header(3), chunk(&pb.Edge{Src: 0, Dest: 1}), header(3)},
+		// Negative total number of vertices
+		{header(-1)},
+		// Total number of vertices too large for the adjacency structure to be allocated
+		{header(math.MaxInt64)},
+		// A node of the second chunk does not exist
+		{header(3), chunk(&pb.Edge{Src: 0, Dest: 1}), chunk(&pb.Edge{Src: 1, Dest: 3})},
+		// Negative weight
+		{header(3), chunk(&pb.Edge{Src: 0, Dest: 1, Weight: proto.Int32(-1)})},
+		// More coordinates than vertices
+		{header(1), points(&pb.Point{}), points(&pb.Point{X: 1})},
+		// Fewer coordinates than vertices
+		{header(3), chunk(&pb.Edge{Src: 0, Dest: 1}), points(&pb.Point{}, &pb.Point{X: 1})},
+		// A heuristic without coordinates
+		{{Data: &pb.PostStreamRequest_Header{Header: &pb.PostStreamHeader{
+			TotalVertices: 2,
+			Heuristic:     pb.Heuristic_HEURISTIC_MANHATTAN,
+		}}}},
+	}
+
+	for i, upload := range uploads {
+		stream, err := client.PostStream(context.Background())
+		if err != nil {
+			t.Fatalf("PostStream got unexpected error: %v", err)
+		}
+
+		for _, req := range upload {
+			// Sending fails once the server ended the stream, and the error is then returned by CloseAndRecv
+			if err = stream.Send(req); err != nil {
+				break
+			}
+		}
+
+		res, err := stream.CloseAndRecv()
+
+		if err == nil {
+			t.Fatal("Failed to catch expected error\n")
+		}
+
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("PostStream upload #%d = %v, %v, expected code: %v", i, res, err, codes.InvalidArgument)
+		}
+	}
+
+	if ids := testServer.store.List(); len(ids) != 0 {
+		t.Errorf("List() = %v, expected no graph", ids)
+	}
+}
@@ -3,7 +3,11 @@ package main
 import (
 	"context"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"math"
 	"testing"
 
 	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
@@ -11,8 +15,7 @@ import (
 
 // TestServer_Post tests for successful posting actions
 func TestServer_Post(t *testing.T) {
-	idHead = 0
-	graphStore = make(map[int32]Graph)
+	testServer.store = newMemoryStore()
 
 	ctx := context.Background()
 	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
@@ -26,8 +29,8 @@ func TestServer_Post(t *testing.T) {
 	client := pb.NewGraphServiceClient(conn)
 
 	tests := []struct {
-		expected      int32
-		totalVertices int32
+		expected      int64
+		totalVertices int64
 		edgesPb       []*pb.Edge
 	}{
 		{
@@ -80,8 +83,7 @@ func TestServer_Post(t *testing.T) {
 
 // TestServer_PostInvalidInput tests for failed posting actions due to invalid arguments
 func TestServer_PostInvalidInput(t *testing.T) {
-	idHead = 0
-	graphStore = make(map[int32]Graph)
+	testServer.store = newMemoryStore()
 
 	ctx := context.Background()
 	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
@@ -168,4 +170,72 @@ func TestServer_PostInvalidInput(t *testing.T) {
 	} else if res5 != nil {
 		t.Fatalf("Post(%+v) = %v, expected: nil", req5, res5.Result)
 	}
+
+	// Weight < 0
+	req6 := &pb.PostRequest{
+		TotalVertices: 3,
+		Edges: []*pb.Edge{
+			{Src: 0, Dest: 1, Weight: proto.Int32(-1)},
+		},
+	}
+
+	res6, err6 := client.Post(context.Background(), req6)
+	if err6 == nil {
+		t.Fatal("Failed to catch expected error\n")
+	} else if res6 != nil {
+		t.Fatalf("Post(%+v) = %v, expected: nil", req6, res6.Result)
+	}
+
+	// TotalVertices too large for the adjacency structure to be allocated
+	for _, totalVertices := range []int64{testServer.maxVertices + 1, math.MaxInt64} {
+		req7 := &pb.PostRequest{TotalVertices: totalVertices}
+
+		res7, err7 := client.Post(context.Background(), req7)
+		if err7 == nil {
+			t.Fatal("Failed to catch expected error\n")
+		} else if res7 != nil {
+			t.Fatalf("Post(%+v) = %v, expected: nil", req7, res7.Result)
+		}
+	}
+}
+
+// TestServer_PostMaxVertices tests that the maximum total number of vertices of the server only applies to the graphs
+// created after it is set, and that the graphs already stored remain readable from disk
+func TestServer_PostMaxVertices(t *testing.T) {
+	store, err := openDiskStore(t.TempDir())
+	if err != nil {
+		t.Fatalf("openDiskStore got unexpected error: %v", err)
+	}
+	testServer.store = store
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	if _, err = client.Post(context.Background(), &pb.PostRequest{TotalVertices: 4}); err != nil {
+		t.Fatalf("Post got unexpected error: %v", err)
+	}
+
+	defer func(maxVertices int64) { testServer.maxVertices = maxVertices }(testServer.maxVertices)
+	testServer.maxVertices = 3
+
+	_, err = client.Post(context.Background(), &pb.PostRequest{TotalVertices: 4})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("Post got %v, expected code: %v", err, codes.InvalidArgument)
+	}
+
+	reopened, err := openDiskStore(store.dir)
+	if err != nil {
+		t.Fatalf("openDiskStore got unexpected error: %v", err)
+	}
+	if graph, ok := reopened.Get(0); !ok || graph.totalVertices != 4 {
+		t.Errorf("Get(0) = %+v, %v, expected a graph of 4 vertices", graph, ok)
+	}
 }
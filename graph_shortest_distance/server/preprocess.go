@@ -0,0 +1,105 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"log"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// defaultLandmarks is the number of landmarks picked by Preprocess when the request does not give one
+const defaultLandmarks = 16
+
+// maxLandmarks is the maximum number of landmarks of a graph, which bounds the memory taken by their distances
+const maxLandmarks = 64
+
+// maxLandmarkDistances is the maximum number of distances kept for the landmarks of a graph, i.e. its number of
+// landmarks times its total number of vertices, twice for a directed graph. It bounds the memory taken by the
+// distances to 2 GiB, which the number of landmarks alone does not on a large graph.
+const maxLandmarkDistances = 1 << 28
+
+// Preprocess picks landmarks in the graph specified in the request, and stores the shortest distances between them and
+// every vertex, so that the subsequent shortest distance queries on the graph are computed by A* search, pruned by the
+// lower bounds the landmarks give. The graph keeps its ID, and is preprocessed again from scratch if it already was.
+// The landmarks are computed without holding the mutation lock, while the queries keep running on the graph as it was
+// before until the landmarks are stored. If the graph is modified in the meantime, the landmarks are discarded and a
+// codes.Aborted error is returned. Modifying the graph also discards the landmarks stored before.
+func (s *Server) Preprocess(ctx context.Context, req *pb.PreprocessRequest) (*pb.PreprocessResponse, error) {
+	log.Printf("Preprocess was invoked with: %v\n", req)
+
+	// Parameter validation
+	count := int(req.Landmarks)
+	if count == 0 {
+		count = defaultLandmarks
+	}
+	if count < 0 || count > maxLandmarks {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			fmt.Sprintf("Invalid number of landmarks: %d. Must be between 1 and %d.", req.Landmarks, maxLandmarks),
+		)
+	}
+
+	graph, err := s.queryGraph(req.Id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(graph.edges) == 0 {
+		return nil, status.Errorf(
+			codes.FailedPrecondition,
+			fmt.Sprintf("The graph[id=%d] has no edges, so it cannot be preprocessed", req.Id),
+		)
+	}
+
+	// The distances from every landmark, and to it in a directed graph, are kept for every vertex
+	distancesPerLandmark := graph.totalVertices
+	if graph.directed {
+		distancesPerLandmark *= 2
+	}
+	if int64(count) > maxLandmarkDistances/distancesPerLandmark {
+		return nil, status.Errorf(
+			codes.InvalidArgument,
+			fmt.Sprintf("Invalid number of landmarks: %d. The graph[id=%d] of %d vertices can have at most %d "+
+				"landmarks.", count, req.Id, graph.totalVertices, maxLandmarkDistances/distancesPerLandmark),
+		)
+	}
+
+	landmarks, err := selectLandmarks(ctx, graph, count)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = s.attachLandmarks(req.Id, graph.version, landmarks); err != nil {
+		return nil, err
+	}
+
+	res := &pb.PreprocessResponse{}
+	for _, vertex := range landmarks.vertices {
+		res.Landmarks = append(res.Landmarks, vertex)
+		if graph.labels != nil {
+			res.LandmarkLabels = append(res.LandmarkLabels, graph.labels.labels[vertex])
+		}
+	}
+
+	return res, nil
+}
+
+// attachLandmarks stores the landmarks computed on the given version of the graph associated with the ID, unless the
+// graph has been modified since then, in which case their distances would be outdated
+func (s *Server) attachLandmarks(id int64, version int64, landmarks *landmarkDistances) error {
+	_, err := s.mutate(id, func(graph Graph) (Graph, error) {
+		if graph.version != version {
+			return Graph{}, status.Errorf(
+				codes.Aborted,
+				fmt.Sprintf("The graph[id=%d] was modified while being preprocessed", id),
+			)
+		}
+
+		graph.landmarks = landmarks
+		return graph, nil
+	})
+	return err
+}
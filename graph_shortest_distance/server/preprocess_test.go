@@ -0,0 +1,363 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// TestServer_Preprocess tests for preprocessing graphs with landmarks, and querying them
+func TestServer_Preprocess(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	graphs := []*pb.PostRequest{
+		{
+			TotalVertices: 6,
+			Edges: []*pb.Edge{
+				{Src: 0, Dest: 1, Weight: proto.Int32(4)},
+				{Src: 0, Dest: 2, Weight: proto.Int32(1)},
+				{Src: 2, Dest: 1, Weight: proto.Int32(1)},
+				{Src: 1, Dest: 3, Weight: proto.Int32(3)},
+				{Src: 4, Dest: 5, Weight: proto.Int32(2)},
+			},
+			Directed: true,
+		},
+		{
+			Labels: []string{"spare"},
+			Edges: []*pb.Edge{
+				{SrcLabel: "a", DestLabel: "b"},
+				{SrcLabel: "b", DestLabel: "c"},
+				{SrcLabel: "x", DestLabel: "y"},
+			},
+		},
+	}
+
+	for _, graph := range graphs {
+		if _, err := client.Post(context.Background(), graph); err != nil {
+			t.Fatalf("Post(%+v) got unexpected error: %v", graph, err)
+		}
+	}
+
+	preprocessed := func(id int64) *pb.GraphInfo {
+		res, err := client.ListGraphs(context.Background(), &pb.ListGraphsRequest{})
+		if err != nil {
+			t.Fatalf("ListGraphs got unexpected error: %v", err)
+		}
+		return res.Graphs[id]
+	}
+
+	if info := preprocessed(0); info.Preprocessed || info.Landmarks != 0 {
+		t.Errorf("ListGraphs got %v before preprocessing, expected not preprocessed", info)
+	}
+
+	res, err := client.Preprocess(context.Background(), &pb.PreprocessRequest{Id: 0, Landmarks: 3})
+	if err != nil {
+		t.Fatalf("Preprocess got unexpected error: %v", err)
+	}
+	if len(res.Landmarks) != 3 {
+		t.Errorf("Preprocess got landmarks %v, expected 3 of them", res.Landmarks)
+	}
+	if info := preprocessed(0); !info.Preprocessed || info.Landmarks != 3 {
+		t.Errorf("ListGraphs got %v after preprocessing, expected 3 landmarks", info)
+	}
+
+	// The labeled graph has fewer vertices having edges than the default number of landmarks
+	res, err = client.Preprocess(context.Background(), &pb.PreprocessRequest{Id: 1})
+	if err != nil {
+		t.Fatalf("Preprocess got unexpected error: %v", err)
+	}
+	if len(res.LandmarkLabels) != len(res.Landmarks) || len(res.Landmarks) == 0 || len(res.Landmarks) > 5 {
+		t.Errorf("Preprocess got landmarks %v (labels: %v), expected between 1 and 5 labeled ones", res.Landmarks,
+			res.LandmarkLabels)
+	}
+
+	tests := []struct {
+		req       *pb.DistRequest
+		expected  int64
+		reachable bool
+	}{
+		{req: &pb.DistRequest{Id: 0, Src: 0, Dest: 3}, expected: 5, reachable: true},
+		{req: &pb.DistRequest{Id: 0, Src: 0, Dest: 1}, expected: 2, reachable: true},
+		{req: &pb.DistRequest{Id: 0, Src: 3, Dest: 0}, expected: 0, reachable: false},
+		{req: &pb.DistRequest{Id: 0, Src: 0, Dest: 5}, expected: 0, reachable: false},
+		{req: &pb.DistRequest{Id: 0, Src: 4, Dest: 5}, expected: 2, reachable: true},
+		{req: &pb.DistRequest{Id: 1, SrcLabel: "a", DestLabel: "c"}, expected: 2, reachable: true},
+		{req: &pb.DistRequest{Id: 1, SrcLabel: "c", DestLabel: "a"}, expected: 2, reachable: true},
+		{req: &pb.DistRequest{Id: 1, SrcLabel: "a", DestLabel: "y"}, expected: 0, reachable: false},
+		{req: &pb.DistRequest{Id: 1, SrcLabel: "spare", DestLabel: "spare"}, expected: 0, reachable: true},
+	}
+
+	for _, tt := range tests {
+		res, err := client.Dist(context.Background(), tt.req)
+		if err != nil {
+			t.Errorf("Dist(%v) got unexpected error: %v", tt.req, err)
+			continue
+		}
+
+		if res.Result != tt.expected || res.Reachable != tt.reachable {
+			t.Errorf("Dist(%v) = %v, expected: %d (reachable: %v)", tt.req, res, tt.expected, tt.reachable)
+		}
+	}
+
+	// Modifying the graph discards its landmarks, whose distances would be outdated
+	if _, err = client.AddEdges(context.Background(), &pb.AddEdgesRequest{
+		Id:    0,
+		Edges: []*pb.Edge{{Src: 3, Dest: 4, Weight: proto.Int32(1)}},
+	}); err != nil {
+		t.Fatalf("AddEdges got unexpected error: %v", err)
+	}
+	if info := preprocessed(0); info.Preprocessed {
+		t.Errorf("ListGraphs got %v after a mutation, expected not preprocessed", info)
+	}
+
+	dist, err := client.Dist(context.Background(), &pb.DistRequest{Id: 0, Src: 0, Dest: 5})
+	if err != nil {
+		t.Fatalf("Dist got unexpected error: %v", err)
+	}
+	if dist.Result != 8 || !dist.Reachable {
+		t.Errorf("Dist from 0 to 5 = %v, expected: %d", dist, 8)
+	}
+}
+
+// TestLandmarkDistance_MatchesDijkstra tests that the search pruned by the landmarks finds the same shortest distances
+// as the exhaustive search, on random graphs of several components, with or without vertex coordinates
+func TestLandmarkDistance_MatchesDijkstra(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for i := 0; i < 200; i++ {
+		totalVertices := 1 + r.Int63n(100)
+		edges := randomEdges(totalVertices, 1+r.Intn(150), int64(i))
+		if i%2 == 0 {
+			for _, edge := range edges {
+				edge.Weight = proto.Int32(r.Int31n(100))
+			}
+		}
+		directed := r.Intn(2) == 0
+
+		exhaustive := newGraph(totalVertices, edges, directed)
+		graph := newGraph(totalVertices, edges, directed)
+		if i%4 == 1 {
+			coordinates := make([]*pb.Point, totalVertices)
+			for j := range coordinates {
+				coordinates[j] = &pb.Point{X: r.Float64() * 100, Y: r.Float64() * 100}
+			}
+			if err := attachCoordinates(&graph, coordinates, pb.Heuristic_HEURISTIC_EUCLIDEAN); err != nil {
+				t.Fatalf("attachCoordinates got unexpected error: %v", err)
+			}
+		}
+
+		landmarks, err := selectLandmarks(context.Background(), graph, 1+r.Intn(8))
+		if err != nil {
+			t.Fatalf("selectLandmarks got unexpected error: %v", err)
+		}
+		graph.landmarks = landmarks
+
+		for j := 0; j < 20; j++ {
+			src, dest := r.Int63n(totalVertices), r.Int63n(totalVertices)
+
+			expected, expectedReachable, _, err := computeShortestDistance(context.Background(), exhaustive, src,
+				dest)
+			if err != nil {
+				t.Fatalf("computeShortestDistance got unexpected error: %v", err)
+			}
+
+			actual, reachable, err := computeDistance(context.Background(), graph, src, dest)
+			if err != nil {
+				t.Fatalf("computeDistance got unexpected error: %v", err)
+			}
+
+			if actual != expected || reachable != expectedReachable {
+				t.Fatalf("Graph #%d: the landmarks %v got %d (reachable: %v) from %d to %d, expected: %d "+
+					"(reachable: %v)", i, landmarks.vertices, actual, reachable, src, dest, expected,
+					expectedReachable)
+			}
+		}
+	}
+}
+
+// TestServer_PreprocessModifiedGraph tests that the landmarks computed on a graph which has been modified since are
+// not stored
+func TestServer_PreprocessModifiedGraph(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	if _, err = client.Post(context.Background(), &pb.PostRequest{
+		TotalVertices: 4,
+		Edges:         []*pb.Edge{{Src: 0, Dest: 1}, {Src: 1, Dest: 2}},
+	}); err != nil {
+		t.Fatalf("Post got unexpected error: %v", err)
+	}
+
+	graph, _ := testServer.store.Get(0)
+	landmarks, err := selectLandmarks(context.Background(), graph, 2)
+	if err != nil {
+		t.Fatalf("selectLandmarks got unexpected error: %v", err)
+	}
+
+	// The graph is modified while its landmarks are computed
+	if _, err = client.AddEdges(context.Background(), &pb.AddEdgesRequest{
+		Id:    0,
+		Edges: []*pb.Edge{{Src: 2, Dest: 3}},
+	}); err != nil {
+		t.Fatalf("AddEdges got unexpected error: %v", err)
+	}
+
+	err = testServer.attachLandmarks(0, graph.version, landmarks)
+	if code := status.Code(err); code != codes.Aborted {
+		t.Fatalf("attachLandmarks got error %v, expected code: %v", err, codes.Aborted)
+	}
+	if current, _ := testServer.store.Get(0); current.landmarks != nil {
+		t.Fatal("attachLandmarks stored the landmarks of a modified graph")
+	}
+
+	// The outdated landmarks would prune the path through the new edge
+	res, err := client.Dist(context.Background(), &pb.DistRequest{Id: 0, Src: 0, Dest: 3})
+	if err != nil {
+		t.Fatalf("Dist got unexpected error: %v", err)
+	}
+	if res.Result != 3 || !res.Reachable {
+		t.Errorf("Dist from 0 to 3 = %v, expected: %d", res, 3)
+	}
+}
+
+// TestServer_PreprocessInvalidInput tests for invalid parameters
+func TestServer_PreprocessInvalidInput(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	for _, graph := range []*pb.PostRequest{
+		{TotalVertices: 3, Edges: []*pb.Edge{{Src: 0, Dest: 1}}},
+		{TotalVertices: 3},
+		{TotalVertices: 1 << 22, Edges: []*pb.Edge{{Src: 0, Dest: 1}}, Directed: true},
+	} {
+		if _, err := client.Post(context.Background(), graph); err != nil {
+			t.Fatalf("Post got unexpected error")
+		}
+	}
+
+	tests := []struct {
+		name     string
+		req      *pb.PreprocessRequest
+		expected codes.Code
+	}{
+		{name: "graph does not exist", req: &pb.PreprocessRequest{Id: 3}, expected: codes.NotFound},
+		{name: "negative number of landmarks", req: &pb.PreprocessRequest{Id: 0, Landmarks: -1},
+			expected: codes.InvalidArgument},
+		{name: "too many landmarks", req: &pb.PreprocessRequest{Id: 0, Landmarks: maxLandmarks + 1},
+			expected: codes.InvalidArgument},
+		{name: "graph without edges", req: &pb.PreprocessRequest{Id: 1}, expected: codes.FailedPrecondition},
+		{name: "too many landmark distances", req: &pb.PreprocessRequest{Id: 2, Landmarks: maxLandmarks},
+			expected: codes.InvalidArgument},
+	}
+
+	for _, tt := range tests {
+		_, err := client.Preprocess(context.Background(), tt.req)
+
+		if err == nil {
+			t.Fatal("Failed to catch expected error\n")
+		}
+
+		if code := status.Code(err); code != tt.expected {
+			t.Errorf("%s: got error code %v, expected: %v", tt.name, code, tt.expected)
+		}
+	}
+}
+
+// TestGraphFile_Landmarks tests that the landmarks are kept by the binary format of the graphs, and that their
+// distances are computed again when the graph is read
+func TestGraphFile_Landmarks(t *testing.T) {
+	graph := newGraph(50, randomEdges(50, 80, 1), true)
+	landmarks, err := selectLandmarks(context.Background(), graph, 4)
+	if err != nil {
+		t.Fatalf("selectLandmarks got unexpected error: %v", err)
+	}
+	graph.landmarks = landmarks
+
+	var buf bytes.Buffer
+	if err = encodeGraphFile(&buf, graph); err != nil {
+		t.Fatalf("encodeGraphFile got unexpected error: %v", err)
+	}
+
+	decoded, err := decodeGraphFile(&buf)
+	if err != nil {
+		t.Fatalf("decodeGraphFile got unexpected error: %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded.landmarks, graph.landmarks) {
+		t.Errorf("decodeGraphFile got landmarks %v, expected: %v", decoded.landmarks, graph.landmarks.vertices)
+	}
+}
+
+// BenchmarkShortestDistance_Landmarks compares the search pruned by the landmarks against Dijkstra's algorithm on a
+// large weighted graph
+func BenchmarkShortestDistance_Landmarks(b *testing.B) {
+	const totalVertices = 250_000
+	edges := randomEdges(totalVertices, 1_000_000, 1)
+	for i, edge := range edges {
+		edge.Weight = proto.Int32(int32(1 + i%100))
+	}
+	graph := newGraph(totalVertices, edges, false)
+
+	landmarks, err := selectLandmarks(context.Background(), graph, defaultLandmarks)
+	if err != nil {
+		b.Fatalf("selectLandmarks got unexpected error: %v", err)
+	}
+	preprocessed := graph
+	preprocessed.landmarks = landmarks
+
+	b.Run("landmarks", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			src := int64(i % totalVertices)
+			dest := int64((i + totalVertices/2) % totalVertices)
+			computeDistance(context.Background(), preprocessed, src, dest)
+		}
+	})
+
+	b.Run("dijkstra", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			src := int64(i % totalVertices)
+			dest := int64((i + totalVertices/2) % totalVertices)
+			computeDistance(context.Background(), graph, src, dest)
+		}
+	})
+}
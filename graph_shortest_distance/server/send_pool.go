@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"google.golang.org/grpc/status"
+	"log"
+	"sync"
+)
+
+// sendTask computes a single response of a streaming RPC, and returns the function sending it over the stream
+type sendTask func() (send func() error, err error)
+
+// sendPool computes the responses of a streaming RPC in parallel by a bounded pool of workers, so the responses are
+// sent as soon as they are computed, which may not be the order of the tasks.
+// Sending is serialized, since a stream does not support concurrent sends. Once a task fails, either to be computed
+// or to be sent, the context of the pool is canceled and the remaining responses are dropped.
+type sendPool struct {
+	tasks  chan sendTask
+	wg     sync.WaitGroup
+	cancel context.CancelFunc
+	sendMu sync.Mutex
+	err    error
+}
+
+// newSendPool starts a pool of the given number of workers, at least one, and returns it along with its context,
+// which is canceled once a task fails
+func newSendPool(ctx context.Context, workers int) (*sendPool, context.Context) {
+	if workers < 1 {
+		workers = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	p := &sendPool{tasks: make(chan sendTask), cancel: cancel}
+
+	for i := 0; i < workers; i++ {
+		p.wg.Add(1)
+		go func() {
+			defer p.wg.Done()
+
+			for task := range p.tasks {
+				p.do(task)
+			}
+		}()
+	}
+
+	return p, ctx
+}
+
+// do computes a single task, and sends its response unless a task has failed already
+func (p *sendPool) do(task sendTask) {
+	send, err := task()
+
+	p.sendMu.Lock()
+	defer p.sendMu.Unlock()
+
+	if p.err != nil {
+		return
+	}
+	if err == nil {
+		if err = send(); err != nil {
+			log.Printf("Error while sending data to client: %v\n", err)
+			err = status.Errorf(
+				status.Code(err),
+				fmt.Sprintf("Error while sending data to client: %v", err),
+			)
+		}
+	}
+	if err != nil {
+		p.err = err
+		p.cancel()
+	}
+}
+
+// run hands a task to the first idle worker, waiting for one if they are all busy
+func (p *sendPool) run(task sendTask) {
+	p.tasks <- task
+}
+
+// wait waits for the workers to be done with the stream, which must happen before the RPC returns, and returns the
+// error of the first task which failed
+func (p *sendPool) wait() error {
+	close(p.tasks)
+	p.wg.Wait()
+	p.cancel()
+
+	return p.err
+}
@@ -1,7 +1,22 @@
 package main
 
-import pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+import (
+	"sync"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
 
 type Server struct {
 	pb.GraphServiceServer
+	// store is the data store keeping the ID -> graph key-value pairs
+	store GraphStore
+	// streamWorkers is the number of requests computed in parallel within a single DistStream, and the number of rows
+	// computed in parallel within a single DistMatrix
+	streamWorkers int
+	// maxVertices is the maximum total number of vertices of the graphs created or grown by the clients. The graphs
+	// already stored are not affected by it, so that it can be changed between restarts.
+	maxVertices int64
+	// mutationMu serializes the in-place graph mutations with each other and with the deletions, so that a mutation
+	// never loses a concurrent update nor restores a deleted graph
+	mutationMu sync.Mutex
 }
@@ -14,10 +14,17 @@ const bufSize = 1024 * 1024
 
 var lis *bufconn.Listener
 
+// testServer is the server behind the mock listener. Each test replaces its data store with an empty one.
+var testServer *Server
+
+// streamErrors receives the error returned by every streaming RPC of the test server, as long as it is not full
+var streamErrors = make(chan error, 16)
+
 func init() {
 	lis = bufconn.Listen(bufSize)
-	s := grpc.NewServer()
-	pb.RegisterGraphServiceServer(s, &Server{})
+	s := grpc.NewServer(grpc.StreamInterceptor(streamErrorsInterceptor))
+	testServer = &Server{store: newMemoryStore(), streamWorkers: 4, maxVertices: defaultMaxVertices}
+	pb.RegisterGraphServiceServer(s, testServer)
 	go func() {
 		if err := s.Serve(lis); err != nil {
 			log.Fatalf("Server exited with error: %v\n", err)
@@ -29,3 +36,25 @@ func init() {
 func bufDialer(context.Context, string) (net.Conn, error) {
 	return lis.Dial()
 }
+
+// streamErrorsInterceptor reports the error returned by every streaming RPC to the streamErrors channel
+func streamErrorsInterceptor(srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo,
+	handler grpc.StreamHandler) error {
+	err := handler(srv, ss)
+	select {
+	case streamErrors <- err:
+	default:
+	}
+	return err
+}
+
+// drainStreamErrors discards the errors reported by the streaming RPCs run so far
+func drainStreamErrors() {
+	for {
+		select {
+		case <-streamErrors:
+		default:
+			return
+		}
+	}
+}
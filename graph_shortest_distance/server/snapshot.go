@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// snapshotFileMagic identifies a snapshot file of the whole memory data store
+const snapshotFileMagic = "GSDS"
+
+// snapshotFileVersion is the version of the binary format written by writeSnapshot.
+// Version 2 added the creation time of the graphs, version 3 added their vertex labels, version 4 widened the vertices
+// and IDs to 64 bits, version 5 added their vertex coordinates, version 6 added their landmarks, and the earlier
+// snapshots are still readable.
+const snapshotFileVersion uint16 = 6
+
+// state returns the ID counter and a copy of the graphs map of the data store.
+// The graphs are never modified once saved, so copying the map is enough to get a consistent view of the data store,
+// which can then be written without blocking the other requests.
+func (s *memoryStore) state() (int64, map[int64]Graph) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	graphs := make(map[int64]Graph, len(s.graphs))
+	for id, graph := range s.graphs {
+		graphs[id] = graph
+	}
+	return atomic.LoadInt64(&s.idHead), graphs
+}
+
+// writeSnapshot writes the graphs along with the ID counter in the versioned binary format of a snapshot file,
+// followed by its checksum
+func writeSnapshot(w io.Writer, idHead int64, graphs map[int64]Graph) error {
+	bw := newBinaryWriter(w)
+	bw.header(snapshotFileMagic, snapshotFileVersion)
+	bw.int64(idHead)
+	bw.uint32(uint32(len(graphs)))
+	for _, id := range sortedIds(graphs) {
+		bw.int64(id)
+		writeGraph(bw, graphs[id])
+	}
+	return bw.checksum()
+}
+
+// readSnapshot reads a data store written by writeSnapshot, and fails if the snapshot is corrupted
+func readSnapshot(r io.Reader) (*memoryStore, error) {
+	br := newBinaryReader(r)
+	version := br.header(snapshotFileMagic, 1, snapshotFileVersion)
+
+	store := newMemoryStore()
+	store.idHead = br.id(version, 4)
+	totalGraphs := br.uint32()
+	encoded := make(map[int64]encodedGraph)
+	for i := uint32(0); i < totalGraphs && br.err == nil; i++ {
+		id := br.id(version, 4)
+		graph := readGraph(br, version)
+		if br.err == nil && (id < 0 || id >= store.idHead) {
+			br.err = fmt.Errorf("invalid graph ID: %d", id)
+		}
+		encoded[id] = graph
+	}
+
+	if err := br.checksum(); err != nil {
+		return nil, err
+	}
+
+	// The graphs are only built once the whole snapshot is known to be intact
+	for id, graph := range encoded {
+		built, err := graph.build()
+		if err != nil {
+			return nil, err
+		}
+		store.graphs[id] = built
+	}
+	return store, nil
+}
+
+// loadSnapshot restores the memory data store from the snapshot file
+func loadSnapshot(path string) (*memoryStore, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return readSnapshot(file)
+}
+
+// snapshotter saves snapshots of the memory data store to a local file, so the graphs and the ID counter can be
+// restored when the server restarts
+type snapshotter struct {
+	store *memoryStore
+	// wal is the optional write-ahead log of the data store, which is compacted once a snapshot is saved
+	wal  *walStore
+	path string
+	// mu serializes the snapshots, so the last one saved is always the most recent one
+	mu sync.Mutex
+}
+
+// save writes a snapshot of the data store, replacing the previous snapshot file only once fully written.
+// The records of the write-ahead log covered by the snapshot are then dropped from the log.
+func (sn *snapshotter) save() error {
+	sn.mu.Lock()
+	defer sn.mu.Unlock()
+
+	if sn.wal == nil {
+		idHead, graphs := sn.store.state()
+		return writeFileAtomic(sn.path, func(w io.Writer) error {
+			return writeSnapshot(w, idHead, graphs)
+		})
+	}
+
+	idHead, graphs, walOffset := sn.wal.state()
+	err := writeFileAtomic(sn.path, func(w io.Writer) error {
+		return writeSnapshot(w, idHead, graphs)
+	})
+	if err != nil {
+		return err
+	}
+
+	// The snapshot has reached the disk, directory entry included, so the records it covers can be dropped
+	return sn.wal.compact(walOffset)
+}
+
+// run saves a snapshot every interval, until the stop channel is closed
+func (sn *snapshotter) run(interval time.Duration, stop <-chan struct{}) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			if err := sn.save(); err != nil {
+				log.Printf("Failed to save snapshot to %s: %v\n", sn.path, err)
+			}
+		case <-stop:
+			return
+		}
+	}
+}
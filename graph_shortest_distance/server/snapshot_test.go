@@ -0,0 +1,148 @@
+package main
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// TestSnapshot_Restore tests that the graphs and the ID counter are restored from a snapshot
+func TestSnapshot_Restore(t *testing.T) {
+	store := newMemoryStore()
+	testServer.store = store
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	reqs := []*pb.PostRequest{
+		{
+			TotalVertices: 4,
+			Edges: []*pb.Edge{
+				{Src: 0, Dest: 1},
+				{Src: 1, Dest: 2},
+				{Src: 3, Dest: 0},
+			},
+		},
+		{
+			TotalVertices: 3,
+			Edges: []*pb.Edge{
+				{Src: 0, Dest: 1, Weight: proto.Int32(5)},
+				{Src: 2, Dest: 1, Weight: proto.Int32(0)},
+			},
+			Directed: true,
+		},
+		{
+			TotalVertices: 2,
+		},
+	}
+
+	for _, req := range reqs {
+		if _, err := client.Post(context.Background(), req); err != nil {
+			t.Fatalf("Post(%v) got unexpected error: %v", req, err)
+		}
+	}
+
+	if _, err = client.Delete(context.Background(), &pb.DeleteRequest{Id: 2}); err != nil {
+		t.Fatalf("Delete got unexpected error: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "snapshot")
+	snapshots := &snapshotter{store: store, path: path}
+	if err = snapshots.save(); err != nil {
+		t.Fatalf("save() got unexpected error: %v", err)
+	}
+
+	restored, err := loadSnapshot(path)
+	if err != nil {
+		t.Fatalf("loadSnapshot(%s) got unexpected error: %v", path, err)
+	}
+
+	if ids := restored.List(); !reflect.DeepEqual(ids, []int64{0, 1}) {
+		t.Errorf("List() = %v, expected: %v", ids, []int64{0, 1})
+	}
+
+	for i, req := range reqs[:2] {
+		graph, ok := restored.Get(int64(i))
+		if !ok {
+			t.Fatalf("Get(%d) did not find the graph", i)
+		}
+		if graph.totalVertices != req.TotalVertices || graph.directed != req.Directed ||
+			len(graph.edges) != len(req.Edges) {
+			t.Fatalf("Get(%d) = %+v, expected: %v", i, graph, req)
+		}
+		for j := range req.Edges {
+			if !proto.Equal(graph.edges[j], req.Edges[j]) {
+				t.Errorf("Get(%d) edge %d = %v, expected: %v", i, j, graph.edges[j], req.Edges[j])
+			}
+		}
+	}
+
+	// The IDs allocated before the snapshot must not be reused
+	if id, _ := restored.NextID(); id != 3 {
+		t.Errorf("NextID() = %d, expected: %d", id, 3)
+	}
+}
+
+// TestSnapshot_Corrupted tests that corrupted snapshots are rejected
+func TestSnapshot_Corrupted(t *testing.T) {
+	store := newMemoryStore()
+	store.Put(0, newGraph(3, []*pb.Edge{{Src: 0, Dest: 1}, {Src: 1, Dest: 2}}, false))
+	store.idHead = 1
+
+	path := filepath.Join(t.TempDir(), "snapshot")
+	if err := (&snapshotter{store: store, path: path}).save(); err != nil {
+		t.Fatalf("save() got unexpected error: %v", err)
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+
+	tests := []struct {
+		name    string
+		content []byte
+	}{
+		{
+			name:    "truncated",
+			content: content[:len(content)-3],
+		},
+		{
+			name:    "flipped bit",
+			content: append(append([]byte{}, content[:10]...), append([]byte{content[10] ^ 1}, content[11:]...)...),
+		},
+		{
+			name:    "unsupported version",
+			content: append(append([]byte{}, content[:4]...), append([]byte{0xff, 0xff}, content[6:]...)...),
+		},
+		{
+			name:    "not a snapshot",
+			content: []byte("not a snapshot file"),
+		},
+	}
+
+	for _, tt := range tests {
+		if err = os.WriteFile(path, tt.content, 0o644); err != nil {
+			t.Fatalf("Failed to write %s: %v", path, err)
+		}
+
+		if _, err = loadSnapshot(path); err == nil {
+			t.Errorf("loadSnapshot(%s) failed to catch expected error", tt.name)
+		}
+	}
+}
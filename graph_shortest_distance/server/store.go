@@ -0,0 +1,83 @@
+package main
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+)
+
+// GraphStore is the data store keeping the ID -> graph key-value pairs.
+// Implementations must be safe for concurrent use by multiple goroutines, and are expected to serve reads from
+// memory, since every query reads the whole graph.
+type GraphStore interface {
+	// NextID allocates a new unique graph ID
+	NextID() (int64, error)
+	// Put saves the graph under the given ID, replacing any graph previously saved under it
+	Put(id int64, graph Graph) error
+	// Get returns the graph saved under the given ID, and whether it exists
+	Get(id int64) (Graph, bool)
+	// Delete removes the graph saved under the given ID, and returns whether it existed
+	Delete(id int64) (bool, error)
+	// List returns the IDs of all the saved graphs in ascending order
+	List() []int64
+}
+
+// memoryStore is an in-memory data store, whose graphs are lost when the server exits
+type memoryStore struct {
+	// idHead is used to keep track of the next ID to assign to the next new graph.
+	// It must only be accessed atomically, and comes first so that it is 64-bit aligned on 32-bit platforms.
+	idHead int64
+	mu     sync.RWMutex
+	graphs map[int64]Graph
+}
+
+// newMemoryStore creates an empty data store, whose first allocated ID is 0
+func newMemoryStore() *memoryStore {
+	return &memoryStore{graphs: make(map[int64]Graph)}
+}
+
+func (s *memoryStore) NextID() (int64, error) {
+	return atomic.AddInt64(&s.idHead, 1) - 1, nil
+}
+
+func (s *memoryStore) Put(id int64, graph Graph) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.graphs[id] = graph
+	return nil
+}
+
+func (s *memoryStore) Get(id int64) (Graph, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	graph, ok := s.graphs[id]
+	return graph, ok
+}
+
+func (s *memoryStore) Delete(id int64) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.graphs[id]
+	delete(s.graphs, id)
+	return ok, nil
+}
+
+func (s *memoryStore) List() []int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return sortedIds(s.graphs)
+}
+
+// sortedIds returns the keys of the graphs map in ascending order
+func sortedIds(graphs map[int64]Graph) []int64 {
+	ids := make([]int64, 0, len(graphs))
+	for id := range graphs {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"sync"
+	"testing"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// TestServer_ConcurrentAccess stress tests the data store with concurrent Post, Dist and Delete requests.
+// Run it with the -race flag to detect unsynchronized accesses.
+func TestServer_ConcurrentAccess(t *testing.T) {
+	testServer.store = newMemoryStore()
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	const workers = 16
+	const rounds = 50
+
+	edgesPb := []*pb.Edge{
+		{Src: 0, Dest: 1},
+		{Src: 1, Dest: 2},
+		{Src: 2, Dest: 3},
+	}
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	ids := make(map[int64]bool)
+
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			for i := 0; i < rounds; i++ {
+				postRes, err := client.Post(context.Background(), &pb.PostRequest{
+					TotalVertices: 4,
+					Edges:         edgesPb,
+				})
+				if err != nil {
+					t.Errorf("Post got unexpected error: %v", err)
+					return
+				}
+
+				mu.Lock()
+				if ids[postRes.Result] {
+					t.Errorf("Post returned duplicate ID: %d", postRes.Result)
+				}
+				ids[postRes.Result] = true
+				mu.Unlock()
+
+				distRes, err := client.Dist(context.Background(), &pb.DistRequest{
+					Id:   postRes.Result,
+					Src:  0,
+					Dest: 3,
+				})
+				if err != nil {
+					t.Errorf("Dist got unexpected error: %v", err)
+					return
+				}
+				if distRes.Result != 3 {
+					t.Errorf("Dist = %v, expected: %v", distRes.Result, 3)
+				}
+
+				// Query a graph which might be concurrently deleted by another worker
+				_, err = client.Dist(context.Background(), &pb.DistRequest{
+					Id:   postRes.Result / 2,
+					Src:  0,
+					Dest: 3,
+				})
+				if err != nil && status.Code(err) != codes.NotFound {
+					t.Errorf("Dist got unexpected error: %v", err)
+				}
+
+				if i%2 == 0 {
+					delRes, err := client.Delete(context.Background(), &pb.DeleteRequest{Id: postRes.Result})
+					if err != nil {
+						t.Errorf("Delete got unexpected error: %v", err)
+						return
+					}
+					if !delRes.Result {
+						t.Errorf("Delete(%d) = false, expected: true", postRes.Result)
+					}
+				}
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	if len(ids) != workers*rounds {
+		t.Errorf("Got %d unique IDs, expected: %d", len(ids), workers*rounds)
+	}
+}
@@ -0,0 +1,327 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// walFileMagic identifies a write-ahead log file
+const walFileMagic = "GSDW"
+
+// walFileVersion is the version of the binary format of the write-ahead log.
+// Version 2 added the creation time of the graphs, version 3 added their vertex labels, version 4 widened the
+// vertices and IDs to 64 bits, version 5 added their vertex coordinates, and version 6 added their landmarks. A log of
+// an earlier version is still replayed, and then rewritten in the current version before any record is appended to
+// it.
+const walFileVersion uint16 = 6
+
+// The operations recorded in the write-ahead log
+const (
+	walOpPut    uint8 = 1
+	walOpDelete uint8 = 2
+)
+
+// walHeaderSize is the size of the header at the start of the write-ahead log file
+const walHeaderSize = int64(len(walFileMagic) + 2)
+
+// walStore is a memory data store whose mutations are appended to a write-ahead log, and synced to the disk before
+// they are applied and acknowledged, so that no acknowledged mutation is lost when the server exits.
+// The log is replayed on top of the latest snapshot on startup, and compacted once a new snapshot is saved.
+type walStore struct {
+	*memoryStore
+	path string
+	// mu serializes the mutations, so the records are appended in the same order the mutations are applied
+	mu   sync.Mutex
+	file *os.File
+	// size is the current size of the log file
+	size int64
+}
+
+// openWAL replays the write-ahead log on top of the memory data store, and opens the log for appending the next
+// mutations, creating it if needed. An incomplete record at the end of the log, left by a crash in the middle of an
+// append, was never acknowledged and is discarded.
+func openWAL(store *memoryStore, path string) (*walStore, error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	// The log may have just been created, in which case its directory entry must be durable as well before any
+	// record is acknowledged
+	if err = syncDir(filepath.Dir(path)); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	size, upgraded, err := replayWAL(store, file)
+	if err != nil {
+		file.Close()
+		return nil, fmt.Errorf("failed to replay %s: %v", path, err)
+	}
+
+	if upgraded != nil {
+		file.Close()
+		err = writeFileAtomic(path, func(out io.Writer) error {
+			_, err := out.Write(upgraded)
+			return err
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to upgrade %s: %v", path, err)
+		}
+		if file, err = os.OpenFile(path, os.O_RDWR, 0o644); err != nil {
+			return nil, err
+		}
+		size = int64(len(upgraded))
+	}
+
+	// Drop the incomplete record, if any, so the next records are appended right after the last valid one
+	if err = file.Truncate(size); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if _, err = file.Seek(size, io.SeekStart); err != nil {
+		file.Close()
+		return nil, err
+	}
+
+	wal := &walStore{memoryStore: store, path: path, file: file, size: size}
+
+	if size == 0 {
+		if err = wal.append(walHeader()); err != nil {
+			file.Close()
+			return nil, err
+		}
+	}
+
+	return wal, nil
+}
+
+// replayWAL applies the records of the write-ahead log to the memory data store, and returns the size of the valid
+// part of the log. If the log was written in an earlier version of the format, its valid part is also returned
+// re-encoded in the current version.
+func replayWAL(store *memoryStore, r io.Reader) (int64, []byte, error) {
+	br := newBinaryReader(r)
+	if br.atEOF() {
+		return 0, nil, nil
+	}
+
+	version := br.header(walFileMagic, 1, walFileVersion)
+	if br.err == io.ErrUnexpectedEOF {
+		// The server exited while creating the log
+		return 0, nil, nil
+	}
+	if br.err != nil {
+		return 0, nil, br.err
+	}
+
+	var upgraded *bytes.Buffer
+	if version < walFileVersion {
+		upgraded = bytes.NewBuffer(walHeader())
+	}
+
+	// The graphs put by the records are only built once the whole log is read, since a graph may be put many times or
+	// deleted by the later records, and building a preprocessed graph computes the distances of all its landmarks
+	pending := make(map[int64]encodedGraph)
+
+	replayed := 0
+	size := int64(-1)
+	for !br.atEOF() {
+		valid := br.offset
+
+		br.resetChecksum()
+		op := br.uint8()
+		id := br.id(version, 4)
+		var encoded encodedGraph
+		if op == walOpPut {
+			encoded = readGraph(br, version)
+		} else if op != walOpDelete && br.err == nil {
+			br.err = fmt.Errorf("unknown operation: %d", op)
+		}
+
+		if err := br.checksum(); err != nil {
+			log.Printf("Discarding the write-ahead log after %d records: %v\n", replayed, err)
+			size = valid
+			break
+		}
+
+		if upgraded != nil {
+			record, err := walRecord(op, id, encoded)
+			if err != nil {
+				return 0, nil, err
+			}
+			upgraded.Write(record)
+		}
+
+		if op == walOpPut {
+			pending[id] = encoded
+			if id >= store.idHead {
+				store.idHead = id + 1
+			}
+		} else {
+			delete(pending, id)
+			delete(store.graphs, id)
+		}
+		replayed++
+	}
+
+	if size < 0 {
+		log.Printf("Replayed %d records from the write-ahead log\n", replayed)
+		size = br.offset
+	}
+
+	for id, encoded := range pending {
+		graph, err := encoded.build()
+		if err != nil {
+			return 0, nil, err
+		}
+		store.graphs[id] = graph
+	}
+
+	return size, upgradedBytes(upgraded), nil
+}
+
+// upgradedBytes returns the content of the re-encoded log, or nil if the log did not need to be re-encoded
+func upgradedBytes(upgraded *bytes.Buffer) []byte {
+	if upgraded == nil {
+		return nil
+	}
+	return upgraded.Bytes()
+}
+
+// walRecord encodes a record of the write-ahead log, followed by its checksum. The graph is only written for the put
+// operations.
+func walRecord(op uint8, id int64, graph encodedGraph) ([]byte, error) {
+	var record bytes.Buffer
+	bw := newBinaryWriter(&record)
+	bw.uint8(op)
+	bw.int64(id)
+	if op == walOpPut {
+		writeEncodedGraph(bw, graph)
+	}
+	if err := bw.checksum(); err != nil {
+		return nil, err
+	}
+	return record.Bytes(), nil
+}
+
+func (w *walStore) Put(id int64, graph Graph) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	record, err := walRecord(walOpPut, id, newEncodedGraph(graph))
+	if err != nil {
+		return err
+	}
+
+	if err = w.append(record); err != nil {
+		return err
+	}
+	return w.memoryStore.Put(id, graph)
+}
+
+func (w *walStore) Delete(id int64) (bool, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.memoryStore.Get(id); !ok {
+		return false, nil
+	}
+
+	record, err := walRecord(walOpDelete, id, encodedGraph{})
+	if err != nil {
+		return false, err
+	}
+
+	if err = w.append(record); err != nil {
+		return false, err
+	}
+	return w.memoryStore.Delete(id)
+}
+
+// append writes the record at the end of the log and syncs it to the disk. If anything fails, the log is truncated
+// back to its previous size so the next records are not appended after a partial one.
+func (w *walStore) append(record []byte) error {
+	_, err := w.file.Write(record)
+	if err == nil {
+		err = w.file.Sync()
+	}
+	if err != nil {
+		if truncateErr := w.file.Truncate(w.size); truncateErr == nil {
+			w.file.Seek(w.size, io.SeekStart)
+		}
+		return err
+	}
+
+	w.size += int64(len(record))
+	return nil
+}
+
+// state returns the ID counter and a copy of the graphs map of the data store, along with the size of the log at
+// that point, i.e. the part of the log already reflected in the returned state
+func (w *walStore) state() (int64, map[int64]Graph, int64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	idHead, graphs := w.memoryStore.state()
+	return idHead, graphs, w.size
+}
+
+// compact drops the records before the given offset from the log, once they are covered by a saved snapshot.
+// The records appended since then are kept, and would simply be applied again on top of the snapshot if the server
+// exits before the compacted log replaces the current one.
+func (w *walStore) compact(offset int64) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	tail := make([]byte, w.size-offset)
+	if _, err := w.file.ReadAt(tail, offset); err != nil {
+		return err
+	}
+
+	err := writeFileAtomic(w.path, func(out io.Writer) error {
+		if _, err := out.Write(walHeader()); err != nil {
+			return err
+		}
+		_, err := out.Write(tail)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(w.path, os.O_RDWR, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err = file.Seek(0, io.SeekEnd); err != nil {
+		file.Close()
+		return err
+	}
+
+	w.file.Close()
+	w.file = file
+	w.size = walHeaderSize + int64(len(tail))
+	return nil
+}
+
+// Close closes the log file
+func (w *walStore) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	return w.file.Close()
+}
+
+// walHeader returns the header at the start of the write-ahead log file
+func walHeader() []byte {
+	var header bytes.Buffer
+	bw := newBinaryWriter(&header)
+	bw.header(walFileMagic, walFileVersion)
+	bw.w.Flush()
+	return header.Bytes()
+}
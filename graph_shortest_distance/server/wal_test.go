@@ -0,0 +1,221 @@
+package main
+
+import (
+	"context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	pb "github.com/firebearrex/graph-shortest-distance-grpc-server-go/graph_shortest_distance/proto"
+)
+
+// TestWAL_Replay tests that the acknowledged Post and Delete requests are recovered from the write-ahead log
+func TestWAL_Replay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+
+	wal, err := openWAL(newMemoryStore(), path)
+	if err != nil {
+		t.Fatalf("openWAL(%s) got unexpected error: %v", path, err)
+	}
+	testServer.store = wal
+
+	ctx := context.Background()
+	creds := grpc.WithTransportCredentials(insecure.NewCredentials())
+	conn, err := grpc.DialContext(ctx, "bufnet", grpc.WithContextDialer(bufDialer), creds)
+
+	if err != nil {
+		t.Fatalf("Failed to dial bufnet: %v", err)
+	}
+
+	defer conn.Close()
+	client := pb.NewGraphServiceClient(conn)
+
+	for i := 0; i < 4; i++ {
+		_, err := client.Post(context.Background(), &pb.PostRequest{
+			TotalVertices: int64(i + 2),
+			Edges: []*pb.Edge{
+				{Src: 0, Dest: int64(i + 1)},
+			},
+		})
+
+		if err != nil {
+			t.Fatalf("Post got unexpected error: %v", err)
+		}
+	}
+
+	for _, id := range []int64{1, 3} {
+		if _, err := client.Delete(context.Background(), &pb.DeleteRequest{Id: id}); err != nil {
+			t.Fatalf("Delete got unexpected error: %v", err)
+		}
+	}
+	wal.Close()
+
+	// Simulate a crash in the middle of appending a record
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		t.Fatalf("Failed to open %s: %v", path, err)
+	}
+	file.Write([]byte{walOpPut, 4, 0})
+	file.Close()
+
+	store := newMemoryStore()
+	wal, err = openWAL(store, path)
+	if err != nil {
+		t.Fatalf("openWAL(%s) got unexpected error: %v", path, err)
+	}
+	defer wal.Close()
+
+	if ids := store.List(); !reflect.DeepEqual(ids, []int64{0, 2}) {
+		t.Errorf("List() = %v, expected: %v", ids, []int64{0, 2})
+	}
+	for _, id := range []int64{0, 2} {
+		if graph, _ := store.Get(id); graph.totalVertices != id+2 || len(graph.edges) != 1 {
+			t.Errorf("Get(%d) = %+v, expected %d vertices and 1 edge", id, graph, id+2)
+		}
+	}
+
+	// The IDs of the deleted graphs must not be reused
+	if id, _ := store.NextID(); id != 4 {
+		t.Errorf("NextID() = %d, expected: %d", id, 4)
+	}
+
+	// The incomplete record is dropped, so the log can be appended to again
+	if err = wal.Put(4, newGraph(1, nil, false)); err != nil {
+		t.Fatalf("Put(4) got unexpected error: %v", err)
+	}
+	wal.Close()
+
+	store = newMemoryStore()
+	if wal, err = openWAL(store, path); err != nil {
+		t.Fatalf("openWAL(%s) got unexpected error: %v", path, err)
+	}
+	defer wal.Close()
+	if ids := store.List(); !reflect.DeepEqual(ids, []int64{0, 2, 4}) {
+		t.Errorf("List() = %v, expected: %v", ids, []int64{0, 2, 4})
+	}
+}
+
+// TestWAL_Compaction tests that the write-ahead log is compacted once a snapshot is saved, and that the graphs are
+// recovered from the snapshot and the compacted log
+func TestWAL_Compaction(t *testing.T) {
+	dir := t.TempDir()
+	walPath := filepath.Join(dir, "wal")
+	snapshotPath := filepath.Join(dir, "snapshot")
+
+	store := newMemoryStore()
+	wal, err := openWAL(store, walPath)
+	if err != nil {
+		t.Fatalf("openWAL(%s) got unexpected error: %v", walPath, err)
+	}
+	defer wal.Close()
+
+	snapshots := &snapshotter{store: store, wal: wal, path: snapshotPath}
+
+	edges := []*pb.Edge{{Src: 0, Dest: 1}, {Src: 1, Dest: 2}}
+	for id := int64(0); id < 3; id++ {
+		if err = wal.Put(id, newGraph(3, edges, false)); err != nil {
+			t.Fatalf("Put(%d) got unexpected error: %v", id, err)
+		}
+	}
+	store.idHead = 3
+
+	if err = snapshots.save(); err != nil {
+		t.Fatalf("save() got unexpected error: %v", err)
+	}
+
+	info, err := os.Stat(walPath)
+	if err != nil {
+		t.Fatalf("Failed to stat %s: %v", walPath, err)
+	}
+	if info.Size() != walHeaderSize {
+		t.Errorf("Size of the compacted log = %d, expected: %d", info.Size(), walHeaderSize)
+	}
+
+	// Mutations after the snapshot are kept in the log
+	if _, err = wal.Delete(0); err != nil {
+		t.Fatalf("Delete(0) got unexpected error: %v", err)
+	}
+	if err = wal.Put(3, newGraph(3, edges, true)); err != nil {
+		t.Fatalf("Put(3) got unexpected error: %v", err)
+	}
+	wal.Close()
+
+	restored, err := loadSnapshot(snapshotPath)
+	if err != nil {
+		t.Fatalf("loadSnapshot(%s) got unexpected error: %v", snapshotPath, err)
+	}
+	if wal, err = openWAL(restored, walPath); err != nil {
+		t.Fatalf("openWAL(%s) got unexpected error: %v", walPath, err)
+	}
+	defer wal.Close()
+
+	if ids := restored.List(); !reflect.DeepEqual(ids, []int64{1, 2, 3}) {
+		t.Errorf("List() = %v, expected: %v", ids, []int64{1, 2, 3})
+	}
+	if graph, _ := restored.Get(3); !graph.directed {
+		t.Errorf("Get(3) = %+v, expected a directed graph", graph)
+	}
+	if id, _ := restored.NextID(); id != 4 {
+		t.Errorf("NextID() = %d, expected: %d", id, 4)
+	}
+}
+
+// TestWAL_UpgradeVersion1 tests that a log written in version 1 of the format, which does not record the creation
+// time of the graphs, is replayed and then rewritten in the current version
+func TestWAL_UpgradeVersion1(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "wal")
+
+	file, err := os.Create(path)
+	if err != nil {
+		t.Fatalf("Failed to create %s: %v", path, err)
+	}
+	bw := newBinaryWriter(file)
+	bw.header(walFileMagic, 1)
+	bw.w.Flush()
+	for id := int32(0); id < 2; id++ {
+		bw = newBinaryWriter(file)
+		bw.uint8(walOpPut)
+		bw.int32(id)
+		bw.int32(2)
+		bw.bool(false)
+		bw.uint32(1)
+		bw.int32(0)
+		bw.int32(1)
+		bw.bool(false)
+		bw.checksum()
+	}
+	file.Close()
+
+	wal, err := openWAL(newMemoryStore(), path)
+	if err != nil {
+		t.Fatalf("openWAL(%s) got unexpected error: %v", path, err)
+	}
+	if err = wal.Put(2, newGraph(3, nil, true)); err != nil {
+		t.Fatalf("Put(2) got unexpected error: %v", err)
+	}
+	wal.Close()
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", path, err)
+	}
+	if !reflect.DeepEqual(content[:walHeaderSize], walHeader()) {
+		t.Errorf("Header = %v, expected: %v", content[:walHeaderSize], walHeader())
+	}
+
+	store := newMemoryStore()
+	if wal, err = openWAL(store, path); err != nil {
+		t.Fatalf("openWAL(%s) got unexpected error: %v", path, err)
+	}
+	defer wal.Close()
+
+	if ids := store.List(); !reflect.DeepEqual(ids, []int64{0, 1, 2}) {
+		t.Errorf("List() = %v, expected: %v", ids, []int64{0, 1, 2})
+	}
+	if graph, _ := store.Get(1); graph.totalVertices != 2 || len(graph.edges) != 1 || !graph.createdAt.IsZero() {
+		t.Errorf("Get(1) = %+v, expected 2 vertices, 1 edge and no creation time", graph)
+	}
+}